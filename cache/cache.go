@@ -0,0 +1,236 @@
+// Package cache provides an offline-first SQLite-backed cache for raw VTOP
+// responses so features keep working on flaky campus wifi or when a session
+// has expired. Every entry is keyed by (regNo, semID, endpoint) and carries
+// its own TTL; callers decide what to do with a stale hit.
+package cache
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const schemaVersion = 1
+
+// TTLs for the endpoints features currently scrape. Keep these in sync with
+// the `endpoint` string each feature passes to FetchReq.
+const (
+	TTLAttendance   = 15 * time.Minute
+	TTLMarks        = 30 * time.Minute
+	TTLTimetable    = 24 * time.Hour
+	TTLCGPA         = 6 * time.Hour
+	TTLExamSchedule = 12 * time.Hour
+)
+
+// Store is a handle to the on-disk cache database.
+type Store struct {
+	db *sql.DB
+}
+
+// Entry is one cached response.
+type Entry struct {
+	RegNo     string
+	SemID     string
+	Endpoint  string
+	Body      []byte
+	Snapshot  []byte // parsed snapshot, JSON-encoded; optional
+	FetchedAt time.Time
+}
+
+// Open opens (creating if necessary) the cache database at path and runs any
+// pending migrations. Callers should Close the returned Store when done.
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating cache dir: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening cache db: %w", err)
+	}
+
+	store := &Store{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating cache db: %w", err)
+	}
+
+	return store, nil
+}
+
+// DefaultPath returns the cache database path under the user's config dir.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cli-top", "cache.db"), nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) migrate() error {
+	if _, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_meta (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			version INTEGER NOT NULL
+		)
+	`); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS entries (
+			reg_no     TEXT NOT NULL,
+			sem_id     TEXT NOT NULL,
+			endpoint   TEXT NOT NULL,
+			body       BLOB NOT NULL,
+			snapshot   BLOB,
+			fetched_at INTEGER NOT NULL,
+			PRIMARY KEY (reg_no, sem_id, endpoint)
+		)
+	`); err != nil {
+		return err
+	}
+
+	var version int
+	err := s.db.QueryRow(`SELECT version FROM schema_meta WHERE id = 1`).Scan(&version)
+	if err == sql.ErrNoRows {
+		_, err = s.db.Exec(`INSERT INTO schema_meta (id, version) VALUES (1, ?)`, schemaVersion)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// No migrations beyond v1 yet; future schema bumps branch on `version` here.
+	if version < schemaVersion {
+		_, err = s.db.Exec(`UPDATE schema_meta SET version = ? WHERE id = 1`, schemaVersion)
+	}
+	return err
+}
+
+// Put stores (or replaces) the entry for (regNo, semID, endpoint).
+func (s *Store) Put(e Entry) error {
+	_, err := s.db.Exec(`
+		INSERT INTO entries (reg_no, sem_id, endpoint, body, snapshot, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(reg_no, sem_id, endpoint) DO UPDATE SET
+			body = excluded.body,
+			snapshot = excluded.snapshot,
+			fetched_at = excluded.fetched_at
+	`, e.RegNo, e.SemID, e.Endpoint, e.Body, e.Snapshot, e.FetchedAt.Unix())
+	return err
+}
+
+// Get returns the cached entry for (regNo, semID, endpoint), if any.
+func (s *Store) Get(regNo, semID, endpoint string) (Entry, bool, error) {
+	var e Entry
+	var fetchedAt int64
+	e.RegNo, e.SemID, e.Endpoint = regNo, semID, endpoint
+
+	row := s.db.QueryRow(`
+		SELECT body, snapshot, fetched_at FROM entries
+		WHERE reg_no = ? AND sem_id = ? AND endpoint = ?
+	`, regNo, semID, endpoint)
+
+	if err := row.Scan(&e.Body, &e.Snapshot, &fetchedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return Entry{}, false, nil
+		}
+		return Entry{}, false, err
+	}
+
+	e.FetchedAt = time.Unix(fetchedAt, 0)
+	return e, true, nil
+}
+
+// Fresh reports whether e was fetched within ttl of now.
+func (e Entry) Fresh(ttl time.Duration, now time.Time) bool {
+	return now.Sub(e.FetchedAt) < ttl
+}
+
+// Prune deletes every entry whose age exceeds its endpoint's TTL, using ttlFor
+// to resolve a TTL per endpoint. It returns the number of rows removed.
+func (s *Store) Prune(ttlFor func(endpoint string) time.Duration) (int, error) {
+	rows, err := s.db.Query(`SELECT reg_no, sem_id, endpoint, fetched_at FROM entries`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	type key struct{ regNo, semID, endpoint string }
+	var stale []key
+	now := time.Now()
+
+	for rows.Next() {
+		var k key
+		var fetchedAt int64
+		if err := rows.Scan(&k.regNo, &k.semID, &k.endpoint, &fetchedAt); err != nil {
+			return 0, err
+		}
+		if now.Sub(time.Unix(fetchedAt, 0)) >= ttlFor(k.endpoint) {
+			stale = append(stale, k)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, k := range stale {
+		if _, err := s.db.Exec(`
+			DELETE FROM entries WHERE reg_no = ? AND sem_id = ? AND endpoint = ?
+		`, k.regNo, k.semID, k.endpoint); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(stale), nil
+}
+
+// All returns every cached entry, for use by `cache export`.
+func (s *Store) All() ([]Entry, error) {
+	rows, err := s.db.Query(`SELECT reg_no, sem_id, endpoint, body, snapshot, fetched_at FROM entries`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var fetchedAt int64
+		if err := rows.Scan(&e.RegNo, &e.SemID, &e.Endpoint, &e.Body, &e.Snapshot, &fetchedAt); err != nil {
+			return nil, err
+		}
+		e.FetchedAt = time.Unix(fetchedAt, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// TTLForEndpoint maps an endpoint name (as passed to FetchReq) to its TTL.
+func TTLForEndpoint(endpoint string) time.Duration {
+	switch endpoint {
+	case "attendance", "attendance-detail":
+		return TTLAttendance
+	case "marks":
+		return TTLMarks
+	case "timetable":
+		return TTLTimetable
+	case "cgpa":
+		return TTLCGPA
+	case "exam-schedule":
+		return TTLExamSchedule
+	default:
+		return TTLMarks
+	}
+}
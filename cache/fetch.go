@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"cli-top/types"
+)
+
+// Mode selects how Fetch treats the cache relative to a live request.
+type Mode int
+
+const (
+	// ModeNormal serves a fresh cache hit if one exists, otherwise fetches live.
+	ModeNormal Mode = iota
+	// ModeRefresh always fetches live and overwrites the cache entry.
+	ModeRefresh
+	// ModeOffline never fetches live, returning a cache miss as an error.
+	ModeOffline
+)
+
+// FetchFunc matches helpers.FetchReq's signature so Fetch can wrap it without
+// features importing cache directly into their call sites.
+type FetchFunc func(regNo string, cookies types.Cookies, url, semID, payload, method, endpoint string) ([]byte, error)
+
+// Fetch wraps a FetchFunc (normally helpers.FetchReq) with the cache. On a
+// fresh hit it returns the cached body and its fetch time without touching
+// the network. On a miss (or ModeRefresh) it calls fetch, stores the result,
+// and returns the current time as cachedAt.
+func Fetch(store *Store, fetch FetchFunc, regNo string, cookies types.Cookies, url, semID, payload, method, endpoint string) ([]byte, time.Time, error) {
+	return FetchMode(store, ModeNormal, fetch, regNo, cookies, url, semID, payload, method, endpoint)
+}
+
+// FetchMode is Fetch with explicit offline/refresh control, surfaced through
+// the --refresh and --offline cobra flags.
+func FetchMode(store *Store, mode Mode, fetch FetchFunc, regNo string, cookies types.Cookies, url, semID, payload, method, endpoint string) ([]byte, time.Time, error) {
+	ttl := TTLForEndpoint(endpoint)
+
+	if mode != ModeRefresh {
+		if entry, ok, err := store.Get(regNo, semID, endpoint); err == nil && ok {
+			if mode == ModeOffline || entry.Fresh(ttl, time.Now()) {
+				return entry.Body, entry.FetchedAt, nil
+			}
+		}
+	}
+
+	if mode == ModeOffline {
+		return nil, time.Time{}, fmt.Errorf("offline mode: no cached data for %s (%s/%s)", endpoint, regNo, semID)
+	}
+
+	body, err := fetch(regNo, cookies, url, semID, payload, method, endpoint)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	if err := store.Put(Entry{RegNo: regNo, SemID: semID, Endpoint: endpoint, Body: body, FetchedAt: now}); err != nil {
+		return body, now, fmt.Errorf("caching response: %w", err)
+	}
+
+	return body, now, nil
+}
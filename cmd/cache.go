@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"cli-top/cache"
+	"cli-top/vtop"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the local response cache",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cache entries past their TTL",
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := cache.DefaultPath()
+		if err != nil {
+			fmt.Println("Error locating cache:", err)
+			return
+		}
+
+		store, err := cache.Open(path)
+		if err != nil {
+			fmt.Println("Error opening cache:", err)
+			return
+		}
+		defer store.Close()
+
+		removed, err := store.Prune(cache.TTLForEndpoint)
+		if err != nil {
+			fmt.Println("Error pruning cache:", err)
+			return
+		}
+
+		fmt.Printf("Removed %d stale entr%s.\n", removed, plural(removed))
+	},
+}
+
+var cacheExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Dump every cached entry as JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := cache.DefaultPath()
+		if err != nil {
+			fmt.Println("Error locating cache:", err)
+			return
+		}
+
+		store, err := cache.Open(path)
+		if err != nil {
+			fmt.Println("Error opening cache:", err)
+			return
+		}
+		defer store.Close()
+
+		entries, err := store.All()
+		if err != nil {
+			fmt.Println("Error reading cache:", err)
+			return
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Println("Error encoding cache entries:", err)
+		}
+	},
+}
+
+// configureCache points vtop.Client at the on-disk response cache and
+// resolves --refresh/--offline into the cache.Mode every fetch uses from
+// here on. Every command that talks to VTOP calls this via loadSession, so
+// FetchMarks/FetchAttendanceReport/etc. actually get a cache-hit path
+// instead of the cache sitting next to still-uncached fetchers. Opening the
+// cache is best-effort: if it fails, fetches just go straight to VTOP like
+// before this existed.
+func configureCache() {
+	switch {
+	case persistentBoolFlag("refresh"):
+		vtop.Mode = cache.ModeRefresh
+	case persistentBoolFlag("offline"):
+		vtop.Mode = cache.ModeOffline
+	default:
+		vtop.Mode = cache.ModeNormal
+	}
+
+	if vtop.Store != nil {
+		return
+	}
+
+	path, err := cache.DefaultPath()
+	if err != nil {
+		return
+	}
+	store, err := cache.Open(path)
+	if err != nil {
+		return
+	}
+	vtop.Store = store
+}
+
+func persistentBoolFlag(name string) bool {
+	v, _ := rootCmd.PersistentFlags().GetBool(name)
+	return v
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+func init() {
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheExportCmd)
+	rootCmd.AddCommand(cacheCmd)
+
+	rootCmd.PersistentFlags().Bool("refresh", false, "Bypass the local cache and re-fetch from VTOP")
+	rootCmd.PersistentFlags().Bool("offline", false, "Serve only from the local cache, never hit VTOP")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"cli-top/debug"
 	"cli-top/login"
+	"encoding/base64"
 	"fmt"
 	"strings"
 
@@ -18,12 +19,25 @@ var credCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to VTOP",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("NOTE: Your password will be visible.")
+		migrateLegacyConfig()
+
 		username := promptInput("Enter your username: ")
 		password := promptInput("Enter your password: ")
-		key := GenerateAESKey()
 
-		encryptedPassword, err := encryptPassword(password, key)
+		passphrase, err := promptPassphrase("Enter a master passphrase to protect this session: ")
+		if err != nil {
+			fmt.Println("Error reading passphrase:", err)
+			return
+		}
+
+		salt, err := generateSalt()
+		if err != nil {
+			fmt.Println("Error generating salt:", err)
+			return
+		}
+		key := deriveKey(passphrase, salt)
+
+		encryptedPassword, err := encryptGCM(password, key)
 		if err != nil && debug.Debug {
 			fmt.Println("Error encrypting password:", err)
 			return
@@ -46,13 +60,23 @@ var credCmd = &cobra.Command{
 
 		fmt.Printf("✅ Login successful! Registration Number: %s\n", regNo)
 
-		// Save all credentials and cookies
+		encryptedCSRF, err1 := encryptGCM(cookies.CSRF, key)
+		encryptedJSESSIONID, err2 := encryptGCM(cookies.JSESSIONID, key)
+		encryptedSERVERID, err3 := encryptGCM(cookies.SERVERID, key)
+		if err1 != nil || err2 != nil || err3 != nil {
+			fmt.Println("Error encrypting session cookies.")
+			return
+		}
+
+		// Save the password and session cookies encrypted under the
+		// passphrase-derived key. The username, registration number and
+		// salt are stored in the clear.
 		viper.Set("VTOP_USERNAME", "\""+strings.ToUpper(username)+"\"")
 		viper.Set("PASSWORD", "\""+encryptedPassword+"\"")
-		viper.Set("KEY", "\""+key+"\"")
-		viper.Set("CSRF", "\""+cookies.CSRF+"\"")
-		viper.Set("JSESSIONID", "\""+cookies.JSESSIONID+"\"")
-		viper.Set("SERVERID", "\""+cookies.SERVERID+"\"")
+		viper.Set("SALT", "\""+base64.StdEncoding.EncodeToString(salt)+"\"")
+		viper.Set("CSRF", "\""+encryptedCSRF+"\"")
+		viper.Set("JSESSIONID", "\""+encryptedJSESSIONID+"\"")
+		viper.Set("SERVERID", "\""+encryptedSERVERID+"\"")
 		viper.Set("REGNO", "\""+regNo+"\"")
 
 		if err := viper.WriteConfigAs("cli-top-config.env"); err != nil && debug.Debug {
@@ -64,6 +88,20 @@ var credCmd = &cobra.Command{
 	},
 }
 
+// migrateLegacyConfig detects the pre-Argon2id config format (a plaintext
+// "KEY" sitting next to the AES ciphertext it unlocks) and strips it,
+// forcing a clean re-login under the new passphrase-derived scheme rather
+// than silently carrying the weaker secret forward.
+func migrateLegacyConfig() {
+	if viper.GetString("SALT") != "" || viper.GetString("KEY") == "" {
+		return
+	}
+
+	fmt.Println("Found credentials from an older cli-top version. Re-login to upgrade to passphrase-protected storage.")
+	viper.Set("KEY", "")
+	viper.Set("PASSWORD", "")
+}
+
 func promptInput(prompt string) string {
 	fmt.Print(prompt)
 	var input string
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"cli-top/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonConfigPath string
+	daemonInterval   time.Duration
+	daemonSemChoice  int
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Poll VTOP for marks changes in the background and notify configured sinks",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		fileCfg, err := daemon.LoadConfig(daemonConfigPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		notifiers, err := daemon.BuildNotifiers(fileCfg.Notifiers)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(notifiers) == 0 {
+			notifiers = []daemon.Notifier{daemon.DesktopNotifier{}}
+		}
+
+		interval := daemonInterval
+		if fileCfg.IntervalMinutes > 0 {
+			interval = time.Duration(fileCfg.IntervalMinutes) * time.Minute
+		}
+
+		semChoice := daemonSemChoice
+		if fileCfg.SemChoice != 0 {
+			semChoice = fileCfg.SemChoice
+		}
+
+		repo, err := openMarksRepository()
+		if err != nil {
+			fmt.Println("Error opening marks history:", err)
+			return
+		}
+		defer repo.Close()
+
+		d := daemon.New(daemon.Config{
+			RegNo:     regNo,
+			Cookies:   cookies,
+			SemChoice: semChoice,
+			Interval:  interval,
+			Notifiers: notifiers,
+		}, repo)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		fmt.Printf("Polling marks every %s via %d notifier(s)... (Ctrl-C to stop)\n", interval, len(notifiers))
+		d.Run(ctx)
+	},
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&daemonConfigPath, "config", "cli-top-daemon.yaml", "YAML config file listing notifier sinks")
+	daemonCmd.Flags().DurationVar(&daemonInterval, "interval", 15*time.Minute, "Poll interval, overridden by the config file's interval_minutes if set")
+	daemonCmd.Flags().IntVar(&daemonSemChoice, "semester", 5, "Semester selector passed to helpers.SelectSemester (5 = latest)")
+
+	rootCmd.AddCommand(daemonCmd)
+}
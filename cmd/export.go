@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"cli-top/export"
+	"cli-top/features"
+	"cli-top/types"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export VTOP data as JSON, CSV, XLSX, or ICS",
+}
+
+var exportFormatFlag string
+var exportOutFlag string
+
+var exportAttendanceCmd = &cobra.Command{
+	Use:   "attendance",
+	Short: "Export attendance records",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		records, err := features.GetAttendance(regNo, cookies, 5)
+		if err != nil {
+			fmt.Println("Error fetching attendance:", err)
+			return
+		}
+
+		headers := []string{"Course Code", "Course Name", "Type", "Faculty", "Attended", "Total", "Percentage"}
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			rows = append(rows, []string{
+				r.CourseCode, r.CourseName, r.CourseType, r.Faculty,
+				strconv.Itoa(r.Attended), strconv.Itoa(r.Total),
+				fmt.Sprintf("%.2f", r.Percentage),
+			})
+		}
+
+		writeTabular(records, headers, rows, "attendance")
+	},
+}
+
+var exportCgpaCmd = &cobra.Command{
+	Use:   "cgpa",
+	Short: "Export CGPA and grade distribution",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		url := "https://vtop.vit.ac.in/vtop/examinations/examGradeView/doStudentWholeSemesterReport"
+		snapshot, err := features.PrintCgpa(regNo, cookies, url)
+		if err != nil {
+			fmt.Println("Error fetching CGPA:", err)
+			return
+		}
+
+		headers := []string{"Credits Registered", "Credits Earned", "CGPA"}
+		rows := [][]string{{
+			strconv.Itoa(snapshot.CreditsRegistered),
+			strconv.Itoa(snapshot.CreditsEarned),
+			fmt.Sprintf("%.2f", snapshot.CGPA),
+		}}
+
+		writeTabular(snapshot, headers, rows, "cgpa")
+	},
+}
+
+var exportTimetableCmd = &cobra.Command{
+	Use:   "timetable",
+	Short: "Export the weekly timetable as JSON or CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		entries, err := features.GetTimetable(regNo, cookies)
+		if err != nil {
+			fmt.Println("Error fetching timetable:", err)
+			return
+		}
+
+		format, err := export.ParseFormat(firstNonEmpty(exportFormatFlag, "json"))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if format == export.FormatICS {
+			fmt.Println("Error: ics isn't supported for the timetable yet — cli-top only scrapes each class's day of week, not its real start/end time, so every event would land at a fabricated midnight slot. Use --format json or --format csv instead.")
+			return
+		}
+
+		headers := []string{"Day", "Course"}
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{e.Day, e.Course})
+		}
+
+		writeTabular(entries, headers, rows, "timetable")
+	},
+}
+
+var exportExamsCmd = &cobra.Command{
+	Use:   "exams",
+	Short: "Export the exam schedule as an ICS feed",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		exams, err := features.GetExams(regNo, cookies)
+		if err != nil {
+			fmt.Println("Error fetching exams:", err)
+			return
+		}
+
+		events := make([]export.ICSEvent, 0, len(exams))
+		for i, e := range exams {
+			events = append(events, export.ICSEvent{
+				UID:      fmt.Sprintf("exam-%d@cli-top", i),
+				Summary:  fmt.Sprintf("%s Exam", e.CourseCode),
+				Location: e.Venue,
+				Start:    e.StartsAt,
+				End:      e.StartsAt.Add(e.Duration),
+			})
+		}
+
+		writeOutput(func(w *os.File) error {
+			return export.WriteICS(w, "VTOP Exams", events)
+		}, "exams.ics")
+	},
+}
+
+// loadSession reads the saved registration number and cookies from viper,
+// decrypting the cookies with the passphrase-derived key (see keyring.go).
+func loadSession() (string, types.Cookies, bool) {
+	regNo := viper.GetString("REGNO")
+	if regNo == "" {
+		fmt.Println("Please login using the cli-top login command.")
+		return "", types.Cookies{}, false
+	}
+
+	cookies, err := decryptSessionCookies()
+	if err != nil {
+		fmt.Println("Error unlocking session:", err)
+		return "", types.Cookies{}, false
+	}
+
+	configureCache()
+
+	return regNo, cookies, true
+}
+
+// writeTabular renders v according to --format, defaulting to JSON since
+// these commands are meant for scripting rather than the colored tables the
+// interactive commands already print.
+func writeTabular(v any, headers []string, rows [][]string, defaultName string) {
+	format, err := export.ParseFormat(firstNonEmpty(exportFormatFlag, "json"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	switch format {
+	case export.FormatJSON:
+		writeOutput(func(w *os.File) error { return export.WriteJSON(w, v) }, defaultName+".json")
+	case export.FormatCSV:
+		writeOutput(func(w *os.File) error { return export.WriteCSV(w, headers, rows) }, defaultName+".csv")
+	case export.FormatXLSX:
+		path := firstNonEmpty(exportOutFlag, defaultName+".xlsx")
+		if err := export.WriteXLSX(path, defaultName, headers, rows); err != nil {
+			fmt.Println("Error writing xlsx:", err)
+			return
+		}
+		fmt.Println("Wrote", path)
+	default:
+		fmt.Printf("Format %q is not supported for this export.\n", format)
+	}
+}
+
+// writeOutput calls write with either os.Stdout or the --out file.
+func writeOutput(write func(w *os.File) error, defaultName string) {
+	if exportOutFlag == "" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Println("Error writing output:", err)
+		}
+		return
+	}
+
+	f, err := os.Create(exportOutFlag)
+	if err != nil {
+		fmt.Println("Error creating output file:", err)
+		return
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		fmt.Println("Error writing output:", err)
+		return
+	}
+	fmt.Println("Wrote", exportOutFlag)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVar(&exportFormatFlag, "format", "json", "Output format: json, csv, xlsx, or ics")
+	exportCmd.PersistentFlags().StringVar(&exportOutFlag, "out", "", "Write to this file instead of stdout")
+
+	exportCmd.AddCommand(exportAttendanceCmd)
+	exportCmd.AddCommand(exportCgpaCmd)
+	exportCmd.AddCommand(exportTimetableCmd)
+	exportCmd.AddCommand(exportExamsCmd)
+	rootCmd.AddCommand(exportCmd)
+}
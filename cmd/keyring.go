@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"cli-top/types"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/term"
+
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+// Argon2id parameters for deriving the AES-256 key from the user's master
+// passphrase. These match OWASP's current minimum recommendation for
+// interactive logins.
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024 // KiB
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	saltLen       = 16
+
+	keyringService = "cli-top"
+	keyringUser    = "passphrase"
+
+	// passphraseSessionTTL bounds how long the OS keychain will serve a
+	// cached passphrase without re-prompting. This keeps "cache it for the
+	// session" from meaning "cache it forever": past the TTL the entry is
+	// treated as expired and cleared, same as a stale cache.Entry.
+	passphraseSessionTTL = 15 * time.Minute
+)
+
+// cachedPassphrase is what promptPassphrase stores in the OS keychain: the
+// passphrase plus when it was cached, so a stale entry can be told apart
+// from a fresh one instead of being trusted indefinitely.
+type cachedPassphrase struct {
+	Passphrase string    `json:"passphrase"`
+	CachedAt   time.Time `json:"cachedAt"`
+}
+
+// deriveKey turns a user passphrase and random salt into an AES-256 key.
+func deriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+}
+
+// generateSalt returns a fresh random Argon2id salt.
+func generateSalt() ([]byte, error) {
+	salt := make([]byte, saltLen)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+// encryptGCM encrypts plaintext with AES-256-GCM under key, returning
+// base64(nonce || ciphertext).
+func encryptGCM(plaintext string, key []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptGCM reverses encryptGCM.
+func decryptGCM(encoded string, key []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// promptPassphrase reads the master passphrase without echoing it to the
+// terminal. If the OS keychain already has it cached and the cache hasn't
+// passed passphraseSessionTTL, that is used instead and the user isn't
+// prompted again; otherwise the stale entry is cleared and a fresh prompt
+// issued.
+func promptPassphrase(prompt string) (string, error) {
+	if raw, err := keyring.Get(keyringService, keyringUser); err == nil && raw != "" {
+		var cached cachedPassphrase
+		if err := json.Unmarshal([]byte(raw), &cached); err == nil && time.Since(cached.CachedAt) < passphraseSessionTTL {
+			return cached.Passphrase, nil
+		}
+		forgetPassphrase()
+	}
+
+	fmt.Print(prompt)
+	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w", err)
+	}
+
+	passphrase := string(raw)
+	cached, err := json.Marshal(cachedPassphrase{Passphrase: passphrase, CachedAt: time.Now()})
+	if err == nil {
+		_ = keyring.Set(keyringService, keyringUser, string(cached)) // best-effort session cache
+	}
+
+	return passphrase, nil
+}
+
+// forgetPassphrase clears the cached passphrase, used once it's no longer
+// valid (e.g. after `cli-top passwd` rotates it).
+func forgetPassphrase() {
+	_ = keyring.Delete(keyringService, keyringUser)
+}
+
+// sessionKey prompts for the master passphrase and derives the AES-256 key
+// using the salt saved at login.
+func sessionKey() ([]byte, error) {
+	saltB64 := viper.GetString("SALT")
+	if saltB64 == "" {
+		return nil, errors.New("no saved session; run cli-top login")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(saltB64)
+	if err != nil {
+		return nil, fmt.Errorf("decoding salt: %w", err)
+	}
+
+	passphrase, err := promptPassphrase("Enter your master passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	return deriveKey(passphrase, salt), nil
+}
+
+// decryptSessionCookies decrypts the CSRF/JSESSIONID/SERVERID cookies saved
+// by `cli-top login` under the current master passphrase.
+func decryptSessionCookies() (types.Cookies, error) {
+	key, err := sessionKey()
+	if err != nil {
+		return types.Cookies{}, err
+	}
+
+	csrf, err := decryptGCM(viper.GetString("CSRF"), key)
+	if err != nil {
+		return types.Cookies{}, fmt.Errorf("decrypting CSRF (wrong passphrase?): %w", err)
+	}
+	jsessionid, err := decryptGCM(viper.GetString("JSESSIONID"), key)
+	if err != nil {
+		return types.Cookies{}, fmt.Errorf("decrypting JSESSIONID: %w", err)
+	}
+	serverid, err := decryptGCM(viper.GetString("SERVERID"), key)
+	if err != nil {
+		return types.Cookies{}, fmt.Errorf("decrypting SERVERID: %w", err)
+	}
+
+	return types.Cookies{CSRF: csrf, JSESSIONID: jsessionid, SERVERID: serverid}, nil
+}
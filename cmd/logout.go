@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Forget the master passphrase cached in the OS keychain",
+	Run: func(cmd *cobra.Command, args []string) {
+		forgetPassphrase()
+		fmt.Println("Forgot the cached passphrase. You'll be prompted for it again next time.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"cli-top/features"
+	featureexport "cli-top/features/export"
+	"cli-top/helpers"
+	"cli-top/storage"
+	"cli-top/types"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	marksSemChoice    int
+	marksFormat       string
+	marksHistory      bool
+	marksAllSemesters bool
+	marksWorkers      int
+)
+
+var marksCmd = &cobra.Command{
+	Use:   "marks",
+	Short: "View your marks for a semester",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		repo, err := openMarksRepository()
+		if err != nil {
+			fmt.Println("Error opening marks history:", err)
+			return
+		}
+		defer repo.Close()
+
+		if marksHistory {
+			runMarksHistory(repo, regNo)
+			return
+		}
+
+		if marksAllSemesters {
+			runMarksAllSemesters(repo, regNo, cookies)
+			return
+		}
+
+		report, err := features.GetMarks(regNo, cookies, "", marksSemChoice)
+		if err != nil {
+			fmt.Println("Error fetching marks:", err)
+			return
+		}
+
+		if previous, ok, err := repo.LatestFor(regNo, report.SemID); err == nil && ok {
+			if changes := storage.Diff(previous, report); len(changes) > 0 {
+				printMarksChanges(changes)
+			}
+		}
+
+		if err := repo.Save(report); err != nil {
+			fmt.Println("Error saving marks snapshot:", err)
+		}
+
+		if err := writeMarksReport(report); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+// runMarksAllSemesters fetches every semester's marks concurrently via
+// features.GetAllMarks, aborting in-flight requests if the user hits
+// Ctrl-C partway through.
+func runMarksAllSemesters(repo *storage.SQLiteMarksRepository, regNo string, cookies types.Cookies) {
+	allSems, err := helpers.GetSemDetails(cookies, regNo)
+	if err != nil {
+		fmt.Println("Error fetching semester list:", err)
+		return
+	}
+	if len(allSems) == 0 {
+		fmt.Println("No semesters found.")
+		return
+	}
+
+	semIDs := make([]string, len(allSems))
+	for i, sem := range allSems {
+		semIDs[i] = sem.SemID
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	reports, err := features.GetAllMarks(ctx, regNo, cookies, semIDs, marksWorkers)
+	if err != nil {
+		fmt.Println("Error fetching marks:", err)
+	}
+
+	for i, report := range reports {
+		if report.SemID == "" {
+			continue
+		}
+
+		if previous, ok, lerr := repo.LatestFor(regNo, report.SemID); lerr == nil && ok {
+			if changes := storage.Diff(previous, report); len(changes) > 0 {
+				fmt.Printf("%s:\n", semIDs[i])
+				printMarksChanges(changes)
+			}
+		}
+
+		if serr := repo.Save(report); serr != nil {
+			fmt.Println("Error saving marks snapshot:", serr)
+		}
+
+		if werr := writeMarksReport(report); werr != nil {
+			fmt.Println(werr)
+		}
+	}
+}
+
+// writeMarksReport renders report in marksFormat. The "table" format is a
+// no-op here because features.GetMarks already printed it to the terminal
+// as it fetched; for runMarksAllSemesters, "table" means silent (the diff
+// output above already summarizes what changed).
+func writeMarksReport(report types.MarksReport) error {
+	switch marksFormat {
+	case "", "table":
+		return nil
+	case "json":
+		return featureexport.WriteJSON(os.Stdout, report)
+	case "csv":
+		return featureexport.WriteCSV(os.Stdout, report)
+	default:
+		return fmt.Errorf("unsupported format %q; use table, json, or csv", marksFormat)
+	}
+}
+
+func runMarksHistory(repo *storage.SQLiteMarksRepository, regNo string) {
+	semID, ok, err := repo.LatestSemID(regNo)
+	if err != nil {
+		fmt.Println("Error reading marks history:", err)
+		return
+	}
+	if !ok {
+		fmt.Println("No marks history saved yet. Run cli-top marks at least once first.")
+		return
+	}
+
+	history, err := repo.History(regNo, semID)
+	if err != nil {
+		fmt.Println("Error reading marks history:", err)
+		return
+	}
+
+	for i, snapshot := range history {
+		fmt.Printf("Snapshot %d: %d course(s)\n", i+1, len(snapshot.Courses))
+		if i > 0 {
+			for _, change := range storage.Diff(history[i-1], snapshot) {
+				printMarksChange(change)
+			}
+		}
+	}
+}
+
+func printMarksChanges(changes []storage.Change) {
+	fmt.Println("\x1b[33;1mChanges since last check:\x1b[0m")
+	for _, c := range changes {
+		printMarksChange(c)
+	}
+	fmt.Println()
+}
+
+func printMarksChange(c storage.Change) {
+	switch c.Kind {
+	case storage.ChangeNewComponent:
+		fmt.Printf("  + %s %s: %s\n", c.CourseCode, c.Component, c.New)
+	case storage.ChangeScoredMarks:
+		fmt.Printf("  ~ %s %s: %s -> %s\n", c.CourseCode, c.Component, c.Old, c.New)
+	case storage.ChangeStatus:
+		fmt.Printf("  ~ %s %s status: %s -> %s\n", c.CourseCode, c.Component, c.Old, c.New)
+	}
+}
+
+func openMarksRepository() (*storage.SQLiteMarksRepository, error) {
+	path, err := storage.DefaultMarksPath()
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewSQLiteMarksRepository(path)
+}
+
+func init() {
+	marksCmd.Flags().IntVar(&marksSemChoice, "semester", 5, "Semester selector passed to helpers.SelectSemester (5 = latest)")
+	marksCmd.Flags().StringVar(&marksFormat, "format", "table", "Output format: table, json, or csv")
+	marksCmd.Flags().BoolVar(&marksHistory, "history", false, "Show saved marks history and what changed between snapshots")
+	marksCmd.Flags().BoolVar(&marksAllSemesters, "all-semesters", false, "Fetch every semester's marks concurrently instead of just one")
+	marksCmd.Flags().IntVar(&marksWorkers, "workers", features.DefaultMarksWorkers, "Number of semesters to fetch concurrently with --all-semesters")
+	marksCmd.Flags().BoolVar(&features.DumpHTML, "dump-html", false, "Save the raw VTOP marks response(s) under features/testdata for capturing new test snapshots")
+	rootCmd.AddCommand(marksCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var passwdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Rotate the master passphrase protecting your saved credentials",
+	Run: func(cmd *cobra.Command, args []string) {
+		if viper.GetString("SALT") == "" {
+			fmt.Println("No saved session found. Run cli-top login first.")
+			return
+		}
+
+		oldKey, err := sessionKey()
+		if err != nil {
+			fmt.Println("Error unlocking existing session:", err)
+			return
+		}
+
+		password, err := decryptGCM(viper.GetString("PASSWORD"), oldKey)
+		if err != nil {
+			fmt.Println("Incorrect passphrase.")
+			return
+		}
+		cookies, err := decryptSessionCookies()
+		if err != nil {
+			fmt.Println("Incorrect passphrase.")
+			return
+		}
+
+		forgetPassphrase() // the cached entry belongs to the old passphrase
+
+		newPassphrase, err := promptPassphrase("Enter your new master passphrase: ")
+		if err != nil {
+			fmt.Println("Error reading new passphrase:", err)
+			return
+		}
+
+		newSalt, err := generateSalt()
+		if err != nil {
+			fmt.Println("Error generating salt:", err)
+			return
+		}
+		newKey := deriveKey(newPassphrase, newSalt)
+
+		encryptedPassword, err1 := encryptGCM(password, newKey)
+		encryptedCSRF, err2 := encryptGCM(cookies.CSRF, newKey)
+		encryptedJSESSIONID, err3 := encryptGCM(cookies.JSESSIONID, newKey)
+		encryptedSERVERID, err4 := encryptGCM(cookies.SERVERID, newKey)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			fmt.Println("Error re-encrypting saved credentials.")
+			return
+		}
+
+		viper.Set("PASSWORD", "\""+encryptedPassword+"\"")
+		viper.Set("SALT", "\""+base64.StdEncoding.EncodeToString(newSalt)+"\"")
+		viper.Set("CSRF", "\""+encryptedCSRF+"\"")
+		viper.Set("JSESSIONID", "\""+encryptedJSESSIONID+"\"")
+		viper.Set("SERVERID", "\""+encryptedSERVERID+"\"")
+
+		if err := viper.WriteConfigAs("cli-top-config.env"); err != nil {
+			fmt.Println("Error writing to .env file:", err)
+			return
+		}
+
+		fmt.Println("Passphrase rotated successfully.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(passwdCmd)
+}
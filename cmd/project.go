@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"cli-top/features"
+
+	"github.com/spf13/cobra"
+)
+
+var projectCourseFlags []string
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Project your SGPA/CGPA for a hypothetical grade in one or more courses",
+	Long:  "Example: cli-top project --course CSE1001=A --course MAT1011=B",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		hypothetical, err := parseCourseFlags(projectCourseFlags)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		inProgress, err := features.GetInProgressMarks(regNo, cookies)
+		if err != nil {
+			fmt.Println("Error fetching current semester's marks:", err)
+			return
+		}
+
+		cgpaSnapshot, err := features.PrintCgpa(regNo, cookies, "https://vtop.vit.ac.in/vtop/examinations/examGradeView/doStudentWholeSemesterReport")
+		if err != nil {
+			fmt.Println("Error fetching current CGPA:", err)
+			return
+		}
+
+		grades := make(map[string]features.CourseGradeReport, len(hypothetical))
+		for code := range hypothetical {
+			report, err := features.FetchGradeDistribution(regNo, cookies, code)
+			if err != nil {
+				fmt.Printf("Error fetching grade distribution for %s: %v\n", code, err)
+				return
+			}
+			grades[code] = report
+		}
+
+		projection, err := features.BuildProjection(inProgress, hypothetical, grades, cgpaSnapshot.CGPA, cgpaSnapshot.CreditsEarned)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		fmt.Println()
+		for _, c := range projection.Courses {
+			fmt.Printf("%s (%s): hypothetical grade %s, %d credit(s)\n", c.Code, c.Title, c.Grade, c.Credits)
+		}
+		fmt.Println()
+		fmt.Printf("Projected SGPA: \033[32m%.2f\033[0m\n", projection.SGPA)
+		fmt.Printf("Projected CGPA: \033[32m%.2f\033[0m\n", projection.CGPA)
+		fmt.Println()
+	},
+}
+
+// parseCourseFlags turns ["CSE1001=A", "MAT1011=B"] into a courseCode->grade
+// map, upper-casing both sides since VTOP course codes and grades are
+// conventionally uppercase.
+func parseCourseFlags(flags []string) (map[string]string, error) {
+	result := make(map[string]string, len(flags))
+
+	for _, raw := range flags {
+		code, grade, found := strings.Cut(raw, "=")
+		if !found || code == "" || grade == "" {
+			return nil, fmt.Errorf("invalid --course value %q, expected CODE=GRADE", raw)
+		}
+		result[strings.ToUpper(strings.TrimSpace(code))] = strings.ToUpper(strings.TrimSpace(grade))
+	}
+
+	return result, nil
+}
+
+func init() {
+	projectCmd.Flags().StringArrayVar(&projectCourseFlags, "course", nil, "Hypothetical grade for a course, e.g. --course CSE1001=A (repeatable)")
+	rootCmd.AddCommand(projectCmd)
+}
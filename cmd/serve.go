@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cli-top/cache"
+	"cli-top/features"
+	"cli-top/metrics"
+	"cli-top/types"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveMetricsAddr string
+	serveInterval    time.Duration
+)
+
+// serveSnapshot is what gets persisted to the cache between refreshes, so a
+// restart (or a refresh that fails because VTOP is down) can still serve the
+// last known-good values instead of an empty scrape.
+type serveSnapshot struct {
+	Data types.VTOPAIData   `json:"data"`
+	CGPA types.CGPASnapshot `json:"cgpa"`
+}
+
+const serveCacheEndpoint = "serve-aggregate"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Expose attendance/marks/CGPA data as a Prometheus metrics endpoint",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		path, err := cache.DefaultPath()
+		if err != nil {
+			fmt.Println("Error locating cache:", err)
+			return
+		}
+		store, err := cache.Open(path)
+		if err != nil {
+			fmt.Println("Error opening cache:", err)
+			return
+		}
+		defer store.Close()
+
+		refresh := func() {
+			data, err := features.BuildAIData(regNo, cookies)
+			if err != nil {
+				fmt.Println("serve: partial data collection error:", err)
+			}
+
+			cgpaSnapshot, cgpaErr := features.PrintCgpa(regNo, cookies, "https://vtop.vit.ac.in/vtop/examinations/examGradeView/doStudentWholeSemesterReport")
+			if cgpaErr != nil {
+				fmt.Println("serve: error refreshing CGPA:", cgpaErr)
+			}
+
+			snapshot := serveSnapshot{Data: data, CGPA: cgpaSnapshot}
+			refreshedAt := time.Now()
+
+			// A fully empty result (VTOP unreachable, session expired) means
+			// every field is a zero value: fall back to the last cached
+			// snapshot instead, so the gauges keep reporting real numbers
+			// and vtop_last_refresh_timestamp is the only thing that goes
+			// stale for Grafana to alarm on.
+			if err != nil && len(data.Attendance) == 0 && len(data.Marks) == 0 && len(data.Exams) == 0 {
+				if cached, ok, cacheErr := store.Get(regNo, "", serveCacheEndpoint); cacheErr == nil && ok {
+					var cachedSnapshot serveSnapshot
+					if jsonErr := json.Unmarshal(cached.Snapshot, &cachedSnapshot); jsonErr == nil {
+						metrics.Update(cachedSnapshot.Data, cachedSnapshot.CGPA, cached.FetchedAt)
+						return
+					}
+				}
+				return
+			}
+
+			encoded, jsonErr := json.Marshal(snapshot)
+			if jsonErr == nil {
+				if putErr := store.Put(cache.Entry{RegNo: regNo, SemID: "", Endpoint: serveCacheEndpoint, Body: []byte("{}"), Snapshot: encoded, FetchedAt: refreshedAt}); putErr != nil {
+					fmt.Println("serve: error caching snapshot:", putErr)
+				}
+			}
+
+			metrics.Update(data, cgpaSnapshot, refreshedAt)
+		}
+
+		refresh() // seed the gauges before serving the first scrape
+		go func() {
+			ticker := time.NewTicker(serveInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				refresh()
+			}
+		}()
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+
+		fmt.Printf("Serving Prometheus metrics on %s/metrics (refreshing every %s)\n", serveMetricsAddr, serveInterval)
+		if err := http.ListenAndServe(serveMetricsAddr, mux); err != nil {
+			fmt.Println("serve: HTTP server exited:", err)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveMetricsAddr, "metrics", ":9090", "Address to serve the Prometheus /metrics endpoint on")
+	serveCmd.Flags().DurationVar(&serveInterval, "interval", 15*time.Minute, "How often to refresh the scraped data")
+	rootCmd.AddCommand(serveCmd)
+}
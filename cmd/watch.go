@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cli-top/watch"
+
+	"github.com/kardianos/service"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	watchInterval   time.Duration
+	watchQuietStart int
+	watchQuietEnd   int
+	watchThresholds []string
+	watchInstall    bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Run in the background and notify on attendance breaches and upcoming exams/classes",
+	Run: func(cmd *cobra.Command, args []string) {
+		regNo, cookies, ok := loadSession()
+		if !ok {
+			return
+		}
+
+		cfg := watch.DefaultConfig()
+		cfg.PollInterval = readDuration("WATCH_POLL_INTERVAL", watchInterval)
+		cfg.QuietHourStart = readInt("WATCH_QUIET_START", watchQuietStart)
+		cfg.QuietHourEnd = readInt("WATCH_QUIET_END", watchQuietEnd)
+
+		thresholds, err := parseCourseThresholds(watchThresholds)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg.Thresholds = thresholds
+
+		program := watch.NewProgram(regNo, cookies, cfg)
+
+		svcConfig := &service.Config{
+			Name:        "cli-top-watch",
+			DisplayName: "cli-top attendance/exam watcher",
+			Description: "Polls VTOP and notifies on attendance breaches and upcoming exams.",
+		}
+
+		svc, err := service.New(program, svcConfig)
+		if err != nil {
+			fmt.Println("Error setting up background service:", err)
+			return
+		}
+
+		if watchInstall {
+			if err := svc.Install(); err != nil {
+				fmt.Println("Error installing service:", err)
+				return
+			}
+			fmt.Println("Installed cli-top-watch as a background service. Start it with your OS's service manager.")
+			return
+		}
+
+		fmt.Printf("Watching every %s (quiet hours %02d:00-%02d:00)...\n", cfg.PollInterval, cfg.QuietHourStart, cfg.QuietHourEnd)
+		if err := svc.Run(); err != nil {
+			fmt.Println("watch exited:", err)
+		}
+	},
+}
+
+// parseCourseThresholds turns ["CSE1001=0.80"] into a courseCode->threshold
+// map, mirroring the CODE=VALUE shape `cli-top project` uses for --course.
+// The CODE side must match types.AttendanceRecord.CourseCode as
+// collectAIAttendance populates it (the VTOP course code, e.g. "CSE1001") —
+// that's a different attendance scraper/table from the one `cli-top
+// attendance` prints, so this was unaffected by that scraper's course-code
+// bug (see the chunk0-2 fix).
+func parseCourseThresholds(flags []string) (map[string]float64, error) {
+	result := make(map[string]float64, len(flags))
+
+	for _, raw := range flags {
+		code, value, found := strings.Cut(raw, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid --threshold value %q, expected CODE=0.80", raw)
+		}
+
+		var threshold float64
+		if _, err := fmt.Sscanf(value, "%f", &threshold); err != nil {
+			return nil, fmt.Errorf("invalid threshold %q for %s: %w", value, code, err)
+		}
+
+		result[strings.ToUpper(strings.TrimSpace(code))] = threshold
+	}
+
+	return result, nil
+}
+
+func readDuration(key string, flagValue time.Duration) time.Duration {
+	if s := viper.GetString(key); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d
+		}
+	}
+	return flagValue
+}
+
+func readInt(key string, flagValue int) int {
+	if viper.IsSet(key) {
+		return viper.GetInt(key)
+	}
+	return flagValue
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 10*time.Minute, "How often to poll VTOP")
+	watchCmd.Flags().IntVar(&watchQuietStart, "quiet-start", 22, "Quiet hours start (0-23, local time)")
+	watchCmd.Flags().IntVar(&watchQuietEnd, "quiet-end", 7, "Quiet hours end (0-23, local time)")
+	watchCmd.Flags().StringArrayVar(&watchThresholds, "threshold", nil, "Per-subject attendance threshold override, e.g. --threshold CSE1001=0.80 (repeatable)")
+	watchCmd.Flags().BoolVar(&watchInstall, "install", false, "Install as an OS background service instead of running in the foreground")
+
+	rootCmd.AddCommand(watchCmd)
+}
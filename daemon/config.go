@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spf13/viper"
+)
+
+// NotifierSpec is one entry in the daemon's YAML config, e.g.:
+//
+//	notifiers:
+//	  - type: desktop
+//	  - type: webhook
+//	    url: https://example.com/hook
+//	  - type: matrix
+//	    homeserver: https://matrix.org
+//	    room_id: "!abc:matrix.org"
+//	    access_token: "..."
+type NotifierSpec struct {
+	Type        string `mapstructure:"type"`
+	URL         string `mapstructure:"url"`
+	Homeserver  string `mapstructure:"homeserver"`
+	RoomID      string `mapstructure:"room_id"`
+	AccessToken string `mapstructure:"access_token"`
+}
+
+// FileConfig is the on-disk shape of the daemon's YAML config file.
+type FileConfig struct {
+	IntervalMinutes int            `mapstructure:"interval_minutes"`
+	SemChoice       int            `mapstructure:"sem_choice"`
+	Notifiers       []NotifierSpec `mapstructure:"notifiers"`
+}
+
+// LoadConfig reads and parses the YAML config file at path. A missing file
+// isn't an error here: it just means "no notifiers configured", and the
+// caller falls back to a sane default (see cmd/daemon.go).
+func LoadConfig(path string) (FileConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, notFound := err.(viper.ConfigFileNotFoundError); notFound || errors.Is(err, fs.ErrNotExist) {
+			return FileConfig{}, nil
+		}
+		return FileConfig{}, fmt.Errorf("reading daemon config %s: %w", path, err)
+	}
+
+	var cfg FileConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		return FileConfig{}, fmt.Errorf("parsing daemon config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// BuildNotifiers turns FileConfig.Notifiers into live Notifier instances.
+func BuildNotifiers(specs []NotifierSpec) ([]Notifier, error) {
+	notifiers := make([]Notifier, 0, len(specs))
+
+	for _, spec := range specs {
+		switch spec.Type {
+		case "desktop":
+			notifiers = append(notifiers, DesktopNotifier{})
+		case "webhook":
+			if spec.URL == "" {
+				return nil, fmt.Errorf("webhook notifier missing url")
+			}
+			notifiers = append(notifiers, NewWebhookNotifier(spec.URL))
+		case "matrix":
+			if spec.Homeserver == "" || spec.RoomID == "" || spec.AccessToken == "" {
+				return nil, fmt.Errorf("matrix notifier requires homeserver, room_id, and access_token")
+			}
+			notifiers = append(notifiers, NewMatrixNotifier(spec.Homeserver, spec.RoomID, spec.AccessToken))
+		default:
+			return nil, fmt.Errorf("unknown notifier type %q", spec.Type)
+		}
+	}
+
+	return notifiers, nil
+}
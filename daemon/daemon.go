@@ -0,0 +1,136 @@
+// Package daemon runs features.GetMarksQuiet on a schedule and notifies
+// pluggable sinks whenever the marks diff detects newly published
+// components or changed statuses. It's the headless counterpart to the
+// watch package: no terminal output, and it's driven by a small internal
+// scheduler instead of kardianos/service.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cli-top/features"
+	"cli-top/storage"
+	"cli-top/types"
+)
+
+// Config controls polling cadence, which semester to track, and where
+// notifications go.
+type Config struct {
+	RegNo     string
+	Cookies   types.Cookies
+	SemChoice int
+	Interval  time.Duration
+	Notifiers []Notifier
+}
+
+// Notifier is a sink for "something changed" events. Notify is called
+// sequentially from the daemon's own goroutine, so a slow webhook just
+// delays the next notifier rather than racing one.
+type Notifier interface {
+	Notify(title, body string) error
+}
+
+// scheduler is a single-goroutine ticker loop with a mutation queue, so
+// Use can schedule work (e.g. an on-demand poll triggered by a future
+// `cli-top daemon poll-now` signal) without racing the ticker-driven poll.
+type scheduler struct {
+	mu    sync.Mutex
+	queue chan func()
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{queue: make(chan func(), 8)}
+}
+
+// Use enqueues fn to run exclusively on the scheduler's goroutine.
+func (s *scheduler) Use(ctx context.Context, fn func()) {
+	select {
+	case s.queue <- fn:
+	case <-ctx.Done():
+	}
+}
+
+func (s *scheduler) run(ctx context.Context, interval time.Duration, tick func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	tick()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tick()
+		case fn := <-s.queue:
+			s.mu.Lock()
+			fn()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Daemon polls features.GetMarksQuiet on Config.Interval and notifies
+// Config.Notifiers when storage.Diff finds a change since the last poll.
+type Daemon struct {
+	cfg   Config
+	repo  storage.MarksRepository
+	sched *scheduler
+}
+
+// New builds a Daemon that persists and diffs snapshots through repo, so
+// callers can reuse the same SQLiteMarksRepository `cli-top marks` writes
+// to.
+func New(cfg Config, repo storage.MarksRepository) *Daemon {
+	return &Daemon{cfg: cfg, repo: repo, sched: newScheduler()}
+}
+
+// Run polls immediately, then every Config.Interval, until ctx is
+// cancelled.
+func (d *Daemon) Run(ctx context.Context) {
+	d.sched.run(ctx, d.cfg.Interval, d.poll)
+}
+
+func (d *Daemon) poll() {
+	report, err := features.GetMarksQuiet(d.cfg.RegNo, d.cfg.Cookies, d.cfg.SemChoice)
+	if err != nil {
+		d.notifyAll("cli-top daemon: fetch error", err.Error())
+		return
+	}
+
+	if previous, ok, err := d.repo.LatestFor(d.cfg.RegNo, report.SemID); err == nil && ok {
+		for _, change := range storage.Diff(previous, report) {
+			d.notifyChange(change)
+		}
+	}
+
+	if err := d.repo.Save(report); err != nil {
+		d.notifyAll("cli-top daemon: save error", err.Error())
+	}
+}
+
+func (d *Daemon) notifyChange(c storage.Change) {
+	var title, body string
+	switch c.Kind {
+	case storage.ChangeNewComponent:
+		title = fmt.Sprintf("New marks: %s", c.CourseCode)
+		body = fmt.Sprintf("%s: %s", c.Component, c.New)
+	case storage.ChangeScoredMarks:
+		title = fmt.Sprintf("Marks updated: %s", c.CourseCode)
+		body = fmt.Sprintf("%s: %s -> %s", c.Component, c.Old, c.New)
+	case storage.ChangeStatus:
+		title = fmt.Sprintf("Status changed: %s", c.CourseCode)
+		body = fmt.Sprintf("%s: %s -> %s", c.Component, c.Old, c.New)
+	}
+	d.notifyAll(title, body)
+}
+
+func (d *Daemon) notifyAll(title, body string) {
+	for _, n := range d.cfg.Notifiers {
+		if err := n.Notify(title, body); err != nil {
+			fmt.Println("daemon: notifier error:", err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// DesktopNotifier fires an OS desktop notification via beeep, the same
+// library cli-top watch uses.
+type DesktopNotifier struct{}
+
+func (DesktopNotifier) Notify(title, body string) error {
+	return beeep.Notify(title, body, "")
+}
+
+// WebhookNotifier POSTs {"title":...,"body":...} as JSON to a configured
+// URL, for integrations like a Slack incoming webhook or a custom relay.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: http.DefaultClient}
+}
+
+func (w *WebhookNotifier) Notify(title, body string) error {
+	payload, err := json.Marshal(struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}{title, body})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+// MatrixNotifier posts a plain-text message to a Matrix room via the
+// client-server API's send-message endpoint, for users who'd rather get
+// daemon alerts in a chat than a webhook relay.
+type MatrixNotifier struct {
+	HomeserverURL string
+	RoomID        string
+	AccessToken   string
+	Client        *http.Client
+}
+
+func NewMatrixNotifier(homeserverURL, roomID, accessToken string) *MatrixNotifier {
+	return &MatrixNotifier{
+		HomeserverURL: homeserverURL,
+		RoomID:        roomID,
+		AccessToken:   accessToken,
+		Client:        http.DefaultClient,
+	}
+}
+
+func (m *MatrixNotifier) Notify(title, body string) error {
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%d",
+		strings.TrimRight(m.HomeserverURL, "/"), m.RoomID, time.Now().UnixNano())
+
+	payload, err := json.Marshal(struct {
+		MsgType string `json:"msgtype"`
+		Body    string `json:"body"`
+	}{"m.text", title + ": " + body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.AccessToken)
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("matrix send to room %s returned %s", m.RoomID, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package export formats scraped VTOP data for consumption outside the
+// terminal: JSON/CSV for scripting and spreadsheets, XLSX workbooks, and
+// RFC 5545 iCalendar feeds for exam events. The timetable export does not
+// offer iCalendar output, since timetable entries carry no start time.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+// Format is a user-selected output format, e.g. from --format=json.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+	FormatXLSX  Format = "xlsx"
+	FormatICS   Format = "ics"
+)
+
+// ParseFormat validates a --format flag value.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatTable, FormatJSON, FormatCSV, FormatXLSX, FormatICS:
+		return Format(strings.ToLower(s)), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+// WriteJSON pretty-prints v as JSON to w.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteCSV writes headers followed by rows as CSV to w.
+func WriteCSV(w io.Writer, headers []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteXLSX writes a single-sheet workbook (headers + rows) to path.
+func WriteXLSX(path, sheetName string, headers []string, rows [][]string) error {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet(sheetName)
+	if err != nil {
+		return err
+	}
+
+	headerRow := sheet.AddRow()
+	for _, h := range headers {
+		headerRow.AddCell().SetString(h)
+	}
+
+	for _, row := range rows {
+		xlsxRow := sheet.AddRow()
+		for _, cell := range row {
+			xlsxRow.AddCell().SetString(cell)
+		}
+	}
+
+	return file.Save(path)
+}
+
+// ICSEvent is the subset of an iCalendar VEVENT this package emits.
+type ICSEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+}
+
+// WriteICS hand-emits an RFC 5545 calendar containing events, suitable for
+// subscribing to from Google/Apple Calendar.
+func WriteICS(w io.Writer, calName string, events []ICSEvent) error {
+	b := &strings.Builder{}
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//cli-top//export//EN\r\n")
+	fmt.Fprintf(b, "X-WR-CALNAME:%s\r\n", escapeICS(calName))
+
+	for _, e := range events {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(b, "UID:%s\r\n", escapeICS(e.UID))
+		fmt.Fprintf(b, "DTSTAMP:%s\r\n", time.Now().UTC().Format("20060102T150405Z"))
+		fmt.Fprintf(b, "DTSTART:%s\r\n", e.Start.UTC().Format("20060102T150405Z"))
+		fmt.Fprintf(b, "DTEND:%s\r\n", e.End.UTC().Format("20060102T150405Z"))
+		fmt.Fprintf(b, "SUMMARY:%s\r\n", escapeICS(e.Summary))
+		if e.Description != "" {
+			fmt.Fprintf(b, "DESCRIPTION:%s\r\n", escapeICS(e.Description))
+		}
+		if e.Location != "" {
+			fmt.Fprintf(b, "LOCATION:%s\r\n", escapeICS(e.Location))
+		}
+		b.WriteString("END:VEVENT\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICS escapes the text characters RFC 5545 requires backslash-escaped.
+func escapeICS(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
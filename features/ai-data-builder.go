@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"cli-top/helpers"
 	"cli-top/types"
+	"cli-top/vtop"
 	"fmt"
 	"strings"
 	"time"
@@ -70,28 +71,41 @@ func BuildAIData(regNo string, cookies types.Cookies) (types.VTOPAIData, error)
 	return data, nil
 }
 
+// GetInProgressMarks fetches the current semester's marks, exported for
+// callers outside this package (e.g. the GPA projection command) that need
+// the course list without pulling in the rest of BuildAIData.
+func GetInProgressMarks(regNo string, cookies types.Cookies) ([]types.CourseMarksSummary, error) {
+	return collectAIMarks(regNo, cookies)
+}
+
+// GetTimetable fetches the weekly timetable for the latest semester. It is a
+// thin exported wrapper around collectAITimetable for callers outside this
+// package (e.g. `cli-top export timetable`) that don't need the rest of
+// BuildAIData's aggregation.
+func GetTimetable(regNo string, cookies types.Cookies) ([]types.TimetableEntry, error) {
+	return collectAITimetable(regNo, cookies)
+}
+
+// GetExams fetches the exam schedule of the most recent semester that has
+// one. Like GetTimetable, it is a thin exported wrapper, here around
+// collectAIExams.
+func GetExams(regNo string, cookies types.Cookies) ([]types.ExamEvent, error) {
+	return collectAIExams(regNo, cookies)
+}
+
 // collectAIMarks fetches marks for all courses using the latest semester
 func collectAIMarks(regNo string, cookies types.Cookies) ([]types.CourseMarksSummary, error) {
 	if !helpers.ValidateLogin(cookies) {
 		return nil, fmt.Errorf("invalid login")
 	}
 
-	url := "https://vtop.vit.ac.in/vtop/examinations/doStudentMarkView"
-
 	// Get latest semester
 	semester, err := helpers.SelectSemester(regNo, cookies, 5) // 5 = latest semester
 	if err != nil {
 		return nil, err
 	}
 
-	payload := fmt.Sprintf(
-		"------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"authorizedID\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"semesterSubId\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"_csrf\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J--\r\n",
-		regNo,
-		semester.SemID,
-		cookies.CSRF,
-	)
-
-	bodyText, err := helpers.FetchReq(regNo, cookies, url, semester.SemID, payload, "POST", "marks")
+	bodyText, err := vtop.New(regNo, cookies).FetchMarks(semester.SemID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,8 +115,8 @@ func collectAIMarks(regNo string, cookies types.Cookies) ([]types.CourseMarksSum
 		return nil, err
 	}
 
-	courseDetails := subjectDetails(doc)
-	elements := findElementsByClass(doc, "customTable-level1")
+	courseDetails := subjectDetails(doc, DefaultMarksSchema)
+	elements := findElementsByClass(doc, DefaultMarksSchema.CustomTableSelectors)
 
 	var marks []types.CourseMarksSummary
 
@@ -112,7 +126,7 @@ func collectAIMarks(regNo string, cookies types.Cookies) ([]types.CourseMarksSum
 		}
 
 		selectedElement := elements[idx]
-		marksTable, weightageMark, maxMarkSum := ExtractMarks(selectedElement)
+		marksTable, weightageMark, maxMarkSum := ExtractMarks(selectedElement, DefaultMarksSchema)
 
 		// Convert marksTable ([][]string) to []CourseMarksComponent
 		var components []types.CourseMarksComponent
@@ -155,22 +169,13 @@ func collectAIAttendance(regNo string, cookies types.Cookies) ([]types.Attendanc
 		return nil, fmt.Errorf("invalid login")
 	}
 
-	url := "https://vtop.vit.ac.in/vtop/student/attn_report"
-
 	// Get latest semester
 	semester, err := helpers.SelectSemester(regNo, cookies, 5)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := fmt.Sprintf(
-		"------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"authorizedID\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"semesterSubId\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"_csrf\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J--\r\n",
-		regNo,
-		semester.SemID,
-		cookies.CSRF,
-	)
-
-	bodyText, err := helpers.FetchReq(regNo, cookies, url, semester.SemID, payload, "POST", "attendance")
+	bodyText, err := vtop.New(regNo, cookies).FetchAttendanceReport(semester.SemID)
 	if err != nil {
 		return nil, err
 	}
@@ -256,21 +261,12 @@ func collectAITimetable(regNo string, cookies types.Cookies) ([]types.TimetableE
 		return nil, fmt.Errorf("invalid login")
 	}
 
-	url := "https://vtop.vit.ac.in/vtop/examinations/doSearchCandidateTimetable"
-
 	semester, err := helpers.SelectSemester(regNo, cookies, 5)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := fmt.Sprintf(
-		"------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"authorizedID\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"semesterSubId\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"_csrf\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J--\r\n",
-		regNo,
-		semester.SemID,
-		cookies.CSRF,
-	)
-
-	bodyText, err := helpers.FetchReq(regNo, cookies, url, semester.SemID, payload, "POST", "timetable")
+	bodyText, err := vtop.New(regNo, cookies).FetchTimetable(semester.SemID)
 	if err != nil {
 		return nil, err
 	}
@@ -325,7 +321,7 @@ func collectAICGPA(regNo string, cookies types.Cookies) (float64, []types.CGPASn
 
 	url := "https://vtop.vit.ac.in/vtop/examinations/examGradeView/StudentGradeHistory"
 
-	bodyText, err := helpers.FetchReq(regNo, cookies, url, "", "", "POST", "")
+	bodyText, err := vtop.New(regNo, cookies).FetchCGPA(url, "grade-history")
 	if err != nil {
 		return 0, nil, "", err
 	}
@@ -5,6 +5,7 @@ import (
 	"cli-top/debug"
 	"cli-top/helpers"
 	types "cli-top/types"
+	"cli-top/vtop"
 	"fmt"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -23,16 +24,20 @@ const (
 )
 
 var (
+	reCourseCode  = regexp.MustCompile(`^[^-]*`)
 	reSubjectName = regexp.MustCompile(`-\s*(.*?)\s*-`)
 	reSubjectType = regexp.MustCompile(`[^-]*$`)
 	reProfessor   = regexp.MustCompile(`^(.*?)\s*-\s*`)
 )
 
-func GetAttendance(regNo string, cookies types.Cookies, sem_choice int) {
+// GetAttendance prints the attendance table for the most recent semester
+// that has data and returns the same records as typed data so callers (e.g.
+// `cli-top export attendance`) can reuse the scrape without re-parsing the
+// printed table.
+func GetAttendance(regNo string, cookies types.Cookies, sem_choice int) ([]types.AttendanceRecord, error) {
 	if !helpers.ValidateLogin(cookies) {
-		return
+		return nil, fmt.Errorf("invalid login")
 	}
-	url := "https://vtop.vit.ac.in/vtop/processViewStudentAttendance"
 
 	semDetails, err := helpers.GetSemDetails(cookies, regNo)
 	if err != nil {
@@ -40,22 +45,23 @@ func GetAttendance(regNo string, cookies types.Cookies, sem_choice int) {
 			fmt.Printf("Error fetching semesters: %v\n", err)
 		}
 		fmt.Println("Please login using the cli-top login command.")
-		return
+		return nil, err
 	}
 
 	if len(semDetails) == 0 {
 		fmt.Println("No semesters found.")
-		return
+		return nil, fmt.Errorf("no semesters found")
 	}
 
 	var semID string
 	var attendanceList [][]string
+	var records []types.AttendanceRecord
 	found := false
 
 	// Iterate from the latest semester to the earliest
 	for i := len(semDetails) - 1; i >= 0; i-- {
 		semID = semDetails[i].SemID
-		bodyText, err := helpers.FetchReq(regNo, cookies, url, semID, "UTC", "POST", "")
+		bodyText, err := vtop.New(regNo, cookies).FetchAttendanceDetail(semID)
 		if err != nil {
 			if debug.Debug {
 				fmt.Printf("Error fetching attendance for Semester %s: %v\n", semDetails[i].SemName, err)
@@ -71,7 +77,7 @@ func GetAttendance(regNo string, cookies types.Cookies, sem_choice int) {
 			continue // Try the previous semester
 		}
 
-		attendanceList = findAndSaveAttendance(doc)
+		attendanceList, records = findAndSaveAttendance(doc)
 
 		// Check if attendance data exists (more than header row)
 		if len(attendanceList) > 1 {
@@ -90,22 +96,28 @@ func GetAttendance(regNo string, cookies types.Cookies, sem_choice int) {
 	// If no attendance data found in any semester
 	if !found {
 		fmt.Println("No attendance data available in any semester.")
-		return
+		return nil, fmt.Errorf("no attendance data available in any semester")
 	}
 
 	fmt.Println()
 	helpers.PrintTable(attendanceList, 1)
 	fmt.Println()
+
+	return records, nil
 }
 
-func findAndSaveAttendance(doc *goquery.Document) [][]string {
+// findAndSaveAttendance returns both the ANSI-colored table rows GetAttendance
+// prints and the plain typed records export formatters consume.
+func findAndSaveAttendance(doc *goquery.Document) ([][]string, []types.AttendanceRecord) {
 	var attendanceList [][]string
+	var records []types.AttendanceRecord
 	attendanceList = append(attendanceList, []string{"Subject", "Type", "Faculty Name", "Classes Attended", "Percentage", "75% Alert"})
 
 	table := doc.Find(AttendanceTableSelector)
 	if table.Length() > 0 {
 		table.Find(AttendanceRowsSelector).Each(func(i int, rowSelection *goquery.Selection) {
 			sub_name_and_type := rowSelection.Find(AttendanceCellSelector).Eq(2).Find("span").Text()
+			var sub_code string
 			var sub_name string
 			var sub_type string
 			proff := rowSelection.Find(AttendanceCellSelector).Eq(4).Find("span").Text()
@@ -113,6 +125,9 @@ func findAndSaveAttendance(doc *goquery.Document) [][]string {
 			total := rowSelection.Find(AttendanceCellSelector).Eq(6).Find("span").Text()
 			percent := rowSelection.Find(AttendanceCellSelector).Eq(7).Find("span").Find("span").Text()
 
+			// Extract Course Code (the segment before the first hyphen)
+			sub_code = strings.TrimSpace(reCourseCode.FindString(sub_name_and_type))
+
 			// Extract Subject Name
 			match := reSubjectName.FindStringSubmatch(sub_name_and_type)
 			if len(match) > 1 {
@@ -146,6 +161,9 @@ func findAndSaveAttendance(doc *goquery.Document) [][]string {
 
 			// Calculate 75% Alert
 			var missOrAttend string
+			var percentFloat float64
+			fmt.Sscanf(strings.TrimSuffix(strings.TrimSpace(percent), "%"), "%f", &percentFloat)
+
 			if sub_type == "Lab Only" || sub_type == "Embedded Lab" {
 				attendedInt = attendedInt / 2
 				totalInt = totalInt / 2
@@ -155,6 +173,15 @@ func findAndSaveAttendance(doc *goquery.Document) [][]string {
 			}
 
 			attendanceList = append(attendanceList, []string{sub_name, sub_type, proff, classes_attended, percent, missOrAttend})
+			records = append(records, types.AttendanceRecord{
+				CourseCode: sub_code,
+				CourseName: sub_name,
+				CourseType: sub_type,
+				Faculty:    proff,
+				Attended:   attendedInt,
+				Total:      totalInt,
+				Percentage: percentFloat,
+			})
 		})
 	} else {
 		if debug.Debug {
@@ -163,31 +190,41 @@ func findAndSaveAttendance(doc *goquery.Document) [][]string {
 		fmt.Println("No attendance table found for the selected semester.")
 	}
 
-	return attendanceList
+	return attendanceList, records
 }
 
-func calculateAttendance(attended, total, classtype int) string {
-	// Calculate how many more classes need to be attended to meet 74.01% attendance
-	targetAttendance := 0.7401
-	neededAttendance := targetAttendance * float64(total)
+// DefaultAttendanceThreshold is VIT's minimum attendance requirement.
+const DefaultAttendanceThreshold = 0.7401
+
+// AttendanceGap reports how many classes of slack a student has against
+// threshold: a positive return value is how many more classes can be missed,
+// a negative one is how many more must be attended to reach threshold. It's
+// the pure numeric core calculateAttendance formats for the terminal and
+// that `cli-top watch` (see the watch package) polls per-subject thresholds
+// against.
+func AttendanceGap(attended, total int, threshold float64) int {
+	neededAttendance := threshold * float64(total)
 
-	// If the current attendance is already below the target
 	if float64(attended) < neededAttendance {
-		// Calculate the exact number of additional classes required to meet 74.01%
-		x := (neededAttendance - float64(attended)) / (1 - targetAttendance)
-		x = math.Ceil(x) // Round up to ensure they meet the target after attending whole classes
-		if classtype == 1 {
-			return fmt.Sprintf("\033[31mAttend %d more lab(s)\033[0m", int(x))
-		} else {
-			return fmt.Sprintf("\033[31mAttend %d more class(es)\033[0m", int(x))
-		}
-	} else {
-		// If already at or above the target, calculate how many can be missed
-		canMiss := int(math.Floor((float64(attended) - neededAttendance) / targetAttendance))
+		x := (neededAttendance - float64(attended)) / (1 - threshold)
+		return -int(math.Ceil(x))
+	}
+
+	return int(math.Floor((float64(attended) - neededAttendance) / threshold))
+}
+
+func calculateAttendance(attended, total, classtype int) string {
+	gap := AttendanceGap(attended, total, DefaultAttendanceThreshold)
+
+	if gap < 0 {
 		if classtype == 1 {
-			return fmt.Sprintf("\033[32mCan miss %d lab(s)\033[0m", canMiss)
-		} else {
-			return fmt.Sprintf("\033[32mCan miss %d class(es)\033[0m", canMiss)
+			return fmt.Sprintf("\033[31mAttend %d more lab(s)\033[0m", -gap)
 		}
+		return fmt.Sprintf("\033[31mAttend %d more class(es)\033[0m", -gap)
+	}
+
+	if classtype == 1 {
+		return fmt.Sprintf("\033[32mCan miss %d lab(s)\033[0m", gap)
 	}
+	return fmt.Sprintf("\033[32mCan miss %d class(es)\033[0m", gap)
 }
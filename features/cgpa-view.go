@@ -4,6 +4,7 @@ import (
 	"cli-top/debug"
 	"cli-top/helpers"
 	"cli-top/types"
+	"cli-top/vtop"
 	"fmt"
 	"strings"
 
@@ -27,22 +28,31 @@ const (
 	NGradesIndex           = 10
 )
 
-func PrintCgpa(regNo string, cookies types.Cookies, url string) {
+// PrintCgpa prints the CGPA/grade-distribution table and returns the same
+// figures as a types.CGPASnapshot so callers (e.g. `cli-top export cgpa`)
+// don't have to re-scrape or re-parse the printed table.
+func PrintCgpa(regNo string, cookies types.Cookies, url string) (types.CGPASnapshot, error) {
+	var snapshot types.CGPASnapshot
+
 	if !helpers.ValidateLogin(cookies) {
-		return
+		return snapshot, fmt.Errorf("invalid login")
 	}
 
 	// Fetch the CGPA data
-	body, err := helpers.FetchReq(regNo, cookies, url, "", "", "POST", "")
-	if err != nil && debug.Debug {
-		fmt.Println("Error fetching CGPA data:", err)
-		return
+	body, err := vtop.New(regNo, cookies).FetchCGPA(url, "whole-semester")
+	if err != nil {
+		if debug.Debug {
+			fmt.Println("Error fetching CGPA data:", err)
+		}
+		return snapshot, err
 	}
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
-	if err != nil && debug.Debug {
-		fmt.Println("Error parsing HTML:", err)
-		return
+	if err != nil {
+		if debug.Debug {
+			fmt.Println("Error parsing HTML:", err)
+		}
+		return snapshot, err
 	}
 
 	// Extract and print data from the specified HTML structure
@@ -84,5 +94,19 @@ func PrintCgpa(regNo string, cookies types.Cookies, url string) {
 	helpers.PrintTable(gradesTableData, 0)
 	fmt.Println()
 
-	// Print the credits and CGPA information in line format
+	fmt.Sscanf(strings.TrimSpace(creditsRegistered), "%d", &snapshot.CreditsRegistered)
+	fmt.Sscanf(strings.TrimSpace(creditsEarned), "%d", &snapshot.CreditsEarned)
+	fmt.Sscanf(strings.TrimSpace(cgpa), "%f", &snapshot.CGPA)
+	snapshot.GradeCounts = map[string]string{
+		"S": strings.TrimSpace(sGrades),
+		"A": strings.TrimSpace(aGrades),
+		"B": strings.TrimSpace(bGrades),
+		"C": strings.TrimSpace(cGrades),
+		"D": strings.TrimSpace(dGrades),
+		"E": strings.TrimSpace(eGrades),
+		"F": strings.TrimSpace(fGrades),
+		"N": strings.TrimSpace(nGrades),
+	}
+
+	return snapshot, nil
 }
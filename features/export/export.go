@@ -0,0 +1,51 @@
+// Package export formats a types.MarksReport for consumption outside the
+// terminal, mirroring the report-card pattern: scrape once into a typed
+// struct, then let the presentation layer pick table/json/csv.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"cli-top/types"
+)
+
+// WriteJSON pretty-prints the report as JSON.
+func WriteJSON(w io.Writer, report types.MarksReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// WriteCSV flattens the report to one row per course component.
+func WriteCSV(w io.Writer, report types.MarksReport) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	headers := []string{"Course Code", "Course Title", "Component", "Max Marks", "Weightage", "Status", "Scored Mark", "Weightage Mark"}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, course := range report.Courses {
+		for _, component := range course.Components {
+			row := []string{
+				course.CourseCode,
+				course.CourseTitle,
+				component.Title,
+				strconv.FormatFloat(component.MaxMarks, 'f', -1, 64),
+				strconv.FormatFloat(component.Weightage, 'f', -1, 64),
+				component.Status,
+				strconv.FormatFloat(component.ScoredMarks, 'f', -1, 64),
+				strconv.FormatFloat(component.WeightageMark, 'f', -1, 64),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return cw.Error()
+}
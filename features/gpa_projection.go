@@ -0,0 +1,196 @@
+// features/gpa_projection.go
+package features
+
+import (
+	"bytes"
+	"cli-top/helpers"
+	"cli-top/types"
+	"cli-top/vtop"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// gradePoints is VIT's 10-point scale.
+var gradePoints = map[string]float64{
+	"S": 10, "A": 9, "B": 8, "C": 7, "D": 6, "E": 5, "F": 0,
+}
+
+const (
+	GradeDistributionTableSelector = "table.customTable"
+	GradeDistributionRowsSelector  = "tbody tr"
+	GradeDistributionCellSelector  = "td"
+)
+
+// creditPattern matches the "Credits: N" / "Credit(s): N" label VTOP prints
+// above a course's grade-distribution table.
+var creditPattern = regexp.MustCompile(`(?i)credit(?:s|\(s\))?\s*:?\s*(\d+)`)
+
+// Course is one row of a what-if projection: an in-progress course plus the
+// hypothetical grade the user wants to test.
+type Course struct {
+	Code    string
+	Title   string
+	Credits int
+	Grade   string
+}
+
+// Projection is the result of weighing a set of hypothetical grades against
+// a student's existing academic record.
+type Projection struct {
+	Courses      []Course
+	SGPA         float64
+	CGPA         float64
+	CreditsTotal int
+}
+
+// CourseGradeReport is what FetchGradeDistribution parses out of a course's
+// grade-distribution page: its credit weight, needed to project SGPA/CGPA,
+// and a histogram of grade -> student count.
+type CourseGradeReport struct {
+	Credits   int
+	Histogram map[string]int
+}
+
+// FetchGradeDistribution parses the per-course grade-distribution report
+// VTOP exposes into a CourseGradeReport. This is the only place that knows a
+// course's credit weight, since collectAIMarks' marks page never carries it.
+func FetchGradeDistribution(regNo string, cookies types.Cookies, courseCode string) (CourseGradeReport, error) {
+	if !helpers.ValidateLogin(cookies) {
+		return CourseGradeReport{}, fmt.Errorf("invalid login")
+	}
+
+	bodyText, err := vtop.New(regNo, cookies).FetchGradeDistribution(courseCode)
+	if err != nil {
+		return CourseGradeReport{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyText))
+	if err != nil {
+		return CourseGradeReport{}, err
+	}
+
+	report := CourseGradeReport{Histogram: make(map[string]int)}
+
+	if m := creditPattern.FindStringSubmatch(doc.Text()); m != nil {
+		fmt.Sscanf(m[1], "%d", &report.Credits)
+	}
+
+	doc.Find(GradeDistributionTableSelector + " " + GradeDistributionRowsSelector).Each(func(i int, row *goquery.Selection) {
+		cells := row.Find(GradeDistributionCellSelector)
+		if cells.Length() < 2 {
+			return
+		}
+
+		grade := strings.TrimSpace(cells.Eq(0).Text())
+		if _, ok := gradePoints[grade]; !ok {
+			return
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(cells.Eq(1).Text()))
+		if err == nil {
+			report.Histogram[grade] = count
+		}
+	})
+
+	return report, nil
+}
+
+// ProjectSGPA computes SGPA = Σ(credits·gradePoint) / Σ(credits) for a set of
+// hypothetical course grades, using VIT's S/A/B/C/D/E/F 10-point scale.
+func ProjectSGPA(courses []Course) (float64, error) {
+	var weightedSum float64
+	var creditsSum int
+
+	for _, c := range courses {
+		point, ok := gradePoints[strings.ToUpper(c.Grade)]
+		if !ok {
+			return 0, fmt.Errorf("unknown grade %q for course %s", c.Grade, c.Code)
+		}
+		weightedSum += point * float64(c.Credits)
+		creditsSum += c.Credits
+	}
+
+	if creditsSum == 0 {
+		return 0, fmt.Errorf("no credits to project against")
+	}
+
+	return weightedSum / float64(creditsSum), nil
+}
+
+// ProjectCGPA combines a freshly projected SGPA with the credits and CGPA a
+// student already has on record, as reported by PrintCgpa.
+func ProjectCGPA(currentCGPA float64, creditsEarned int, sgpa float64, newCredits int) float64 {
+	totalCredits := creditsEarned + newCredits
+	if totalCredits == 0 {
+		return 0
+	}
+	return (currentCGPA*float64(creditsEarned) + sgpa*float64(newCredits)) / float64(totalCredits)
+}
+
+// BuildProjection turns a map of courseCode -> hypothetical grade (as parsed
+// from repeated --course CODE=GRADE flags) into a Projection, pulling course
+// titles from the student's in-progress marks and credits from grades, a
+// courseCode -> CourseGradeReport map the caller builds by calling
+// FetchGradeDistribution for each hypothetical course (the marks page itself
+// never reports credits).
+func BuildProjection(inProgress []types.CourseMarksSummary, hypothetical map[string]string, grades map[string]CourseGradeReport, currentCGPA float64, creditsEarned int) (Projection, error) {
+	var courses []Course
+	consumed := make(map[string]bool, len(hypothetical))
+
+	for _, summary := range inProgress {
+		code := strings.ToUpper(summary.CourseCode)
+		grade, ok := hypothetical[code]
+		if !ok {
+			continue
+		}
+		consumed[code] = true
+
+		report, ok := grades[code]
+		if !ok || report.Credits == 0 {
+			return Projection{}, fmt.Errorf("no credit information for %s; grade-distribution lookup may have failed", summary.CourseCode)
+		}
+		courses = append(courses, Course{
+			Code:    summary.CourseCode,
+			Title:   summary.CourseTitle,
+			Credits: report.Credits,
+			Grade:   strings.ToUpper(grade),
+		})
+	}
+
+	if len(courses) == 0 {
+		return Projection{}, fmt.Errorf("none of the requested courses were found in your current semester's marks")
+	}
+
+	var unmatched []string
+	for code := range hypothetical {
+		if !consumed[code] {
+			unmatched = append(unmatched, code)
+		}
+	}
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		return Projection{}, fmt.Errorf("course(s) not found in your current semester's marks: %s", strings.Join(unmatched, ", "))
+	}
+
+	sgpa, err := ProjectSGPA(courses)
+	if err != nil {
+		return Projection{}, err
+	}
+
+	var newCredits int
+	for _, c := range courses {
+		newCredits += c.Credits
+	}
+
+	return Projection{
+		Courses:      courses,
+		SGPA:         sgpa,
+		CGPA:         ProjectCGPA(currentCGPA, creditsEarned, sgpa, newCredits),
+		CreditsTotal: newCredits,
+	}, nil
+}
@@ -5,172 +5,324 @@ import (
 	"cli-top/debug"
 	"cli-top/helpers"
 	"cli-top/types"
+	"cli-top/vtop"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-const (
-	MarksTableContentSelector   = "tr.tableContent"
-	MarksCustomTableSelector    = "customTable-level1"
-	MarksRowsSelector           = "tbody tr"
-	MarksCellSelector           = "td"
-	MarksGPASpanSelector        = "span[style='font-size: 18px; font-weight: bold;']"
-	MarksTitleCellIndex         = 1
-	MarksMaxMarkCellIndex       = 2
-	MarksWeightageCellIndex     = 3
-	MarksStatusCellIndex        = 4
-	MarksScoredMarkCellIndex    = 5
-	MarksWeightageMarkCellIndex = 6
-	CourseCodeCellIndex         = 2
-	CourseTitleCellIndex        = 3
-	CourseTypeCellIndex         = 4
-	CourseFacultyCellIndex      = 6
-	CourseSlotCellIndex         = 7
-)
+// MarksSchema groups every selector and cell index BuildMarksReport needs
+// to parse a VTOP marks page. Selector fields hold fallbacks in priority
+// order: the first selector that matches anything wins, so a VTOP markup
+// tweak (an inline style's attribute order, a renamed class) degrades to
+// the next guess instead of silently producing empty tables.
+type MarksSchema struct {
+	TableContentSelectors []string
+	CustomTableSelectors  []string
+	RowsSelector          string
+	CellSelector          string
+	GPASpanSelectors      []string
+	HeaderCellText        []string // cell text marking a header/separator row to skip: ExtractMarks checks a row's first cell, subjectDetails its course-code cell
+
+	TitleCellIndex         int
+	MaxMarkCellIndex       int
+	WeightageCellIndex     int
+	StatusCellIndex        int
+	ScoredMarkCellIndex    int
+	WeightageMarkCellIndex int
+	CourseCodeCellIndex    int
+	CourseTitleCellIndex   int
+	CourseTypeCellIndex    int
+	CourseFacultyCellIndex int
+	CourseSlotCellIndex    int
+}
 
-func GetMarks(regNo string, cookies types.Cookies, semID string, semChoice int) {
-	if !helpers.ValidateLogin(cookies) {
+// DefaultMarksSchema matches the markup VTOP has served historically, with
+// fallback selectors for the fields known to vary between VTOP skins (the
+// GPA-exempt span's inline style in particular has been seen with its
+// declarations in different orders).
+var DefaultMarksSchema = MarksSchema{
+	TableContentSelectors: []string{"tr.tableContent"},
+	CustomTableSelectors:  []string{"customTable-level1"},
+	RowsSelector:          "tbody tr",
+	CellSelector:          "td",
+	GPASpanSelectors: []string{
+		`span[style='font-size: 18px; font-weight: bold;']`,
+		`span[style*="font-weight: bold"]`,
+		`span[style*="font-weight:bold"]`,
+	},
+	HeaderCellText: []string{"Sl.No.", "Index"},
+
+	TitleCellIndex:         1,
+	MaxMarkCellIndex:       2,
+	WeightageCellIndex:     3,
+	StatusCellIndex:        4,
+	ScoredMarkCellIndex:    5,
+	WeightageMarkCellIndex: 6,
+	CourseCodeCellIndex:    2,
+	CourseTitleCellIndex:   3,
+	CourseTypeCellIndex:    4,
+	CourseFacultyCellIndex: 6,
+	CourseSlotCellIndex:    7,
+}
+
+// DumpHTML, set via `cli-top marks --dump-html`, makes GetMarks save every
+// raw VTOP response under DumpHTMLDir, so capturing a new snapshot for
+// features/testdata is "run the command once" instead of manually copying
+// devtools output.
+var DumpHTML bool
+
+// DumpHTMLDir is where GetMarks saves raw responses when DumpHTML is set.
+var DumpHTMLDir = "features/testdata"
+
+func dumpHTML(name string, body []byte) {
+	if !DumpHTML {
 		return
 	}
+	if err := os.MkdirAll(DumpHTMLDir, 0o755); err != nil {
+		fmt.Println("dump-html:", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(DumpHTMLDir, name), body, 0o644); err != nil {
+		fmt.Println("dump-html:", err)
+	}
+}
+
+// GetMarks prints the marks table for the semester semChoice selects (the
+// semID argument is not used) and returns the same data as a
+// types.MarksReport, so callers that want JSON/CSV (or the diff in the
+// storage package) don't have to re-scrape or re-parse the printed table.
+func GetMarks(regNo string, cookies types.Cookies, semID string, semChoice int) (types.MarksReport, error) {
+	if !helpers.ValidateLogin(cookies) {
+		return types.MarksReport{}, fmt.Errorf("invalid login")
+	}
 
-	url := "https://vtop.vit.ac.in/vtop/examinations/doStudentMarkView"
 	semester, err := helpers.SelectSemester(regNo, cookies, semChoice)
 	if err != nil {
 		helpers.HandleError("fetching semesters", err)
 		fmt.Println()
-		return
+		return types.MarksReport{}, err
 	}
 
-	payload := fmt.Sprintf(
-		"------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"authorizedID\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"semesterSubId\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J\r\nContent-Disposition: form-data; name=\"_csrf\"\r\n\r\n%s\r\n------WebKitFormBoundary9yjNZXu7BBjgQK7J--\r\n",
-		regNo,
-		semester.SemID,
-		cookies.CSRF,
-	)
-
-	bodyText, err := helpers.FetchReq(regNo, cookies, url, semester.SemID, payload, "POST", "marks")
+	bodyText, err := vtop.New(regNo, cookies).FetchMarks(semester.SemID)
 	if err != nil && debug.Debug {
 		fmt.Println(err)
 	}
+	dumpHTML(fmt.Sprintf("marks-%s-%s.html", regNo, semester.SemID), bodyText)
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyText))
 	if err != nil && debug.Debug {
 		fmt.Println(err)
 	}
 
-	courseDetails := subjectDetails(doc)
-
-	elements := findElementsByClass(doc, MarksCustomTableSelector)
+	report := BuildMarksReport(doc, regNo, semester.SemID)
 
-	if len(elements) == 0 {
+	if len(report.Courses) == 0 {
 		fmt.Println()
 		in := "No Data Found"
 		out := fmt.Sprintf("\033[1;31m%s\033[0m", in)
 		fmt.Println(out)
-		return
+		return report, nil
 	}
 
-	for idx, course := range courseDetails {
-		if idx >= len(elements) {
-			if debug.Debug {
-				fmt.Printf("No corresponding table found for course: %s\n", course.CourseTitle)
-			}
-			continue
-		}
-
-		selectedElement := elements[idx]
-		selectedCourseDetail := courseDetails[idx]
-
-		OneSubTable, weightageMark, maxMarkSum := ExtractMarks(selectedElement)
-		if err != nil && debug.Debug {
-			fmt.Println(OneSubTable)
-			fmt.Println(err)
-		}
-		if len(OneSubTable) == 0 {
-			fmt.Printf("No Data Found for %s\n\n", selectedCourseDetail.CourseTitle)
+	for _, course := range report.Courses {
+		if len(course.Components) == 0 {
+			fmt.Printf("No Data Found for %s\n\n", course.CourseTitle)
 			continue
 		}
 
-		courseDetail := fmt.Sprintf("\033[1;34m%s\033[0m", selectedCourseDetail.CourseTitle)
+		courseDetail := fmt.Sprintf("\033[1;34m%s\033[0m", course.CourseTitle)
 		fmt.Println(courseDetail)
 		fmt.Println()
 
 		headers := []string{"Title", "Max Marks", "Weightage %", "Status", "Scored Mark", "Weightage Mark"}
-
-		tableData := append([][]string{headers}, OneSubTable...)
+		tableData := [][]string{headers}
+		for _, component := range course.Components {
+			tableData = append(tableData, []string{
+				component.Title,
+				strconv.FormatFloat(component.MaxMarks, 'f', -1, 64),
+				strconv.FormatFloat(component.Weightage, 'f', -1, 64),
+				component.Status,
+				strconv.FormatFloat(component.ScoredMarks, 'f', -1, 64),
+				strconv.FormatFloat(component.WeightageMark, 'f', -1, 64),
+			})
+		}
 
 		helpers.PrintTable(tableData, 0)
 
-		weightageMarkStr := fmt.Sprintf("\033[32m%.2f\033[0m", weightageMark)
-		maxMarkSumStr := fmt.Sprintf("\033[32m%d\033[0m", maxMarkSum)
+		weightageMarkStr := fmt.Sprintf("\033[32m%.2f\033[0m", course.TotalScored)
+		maxMarkSumStr := fmt.Sprintf("\033[32m%.0f\033[0m", course.TotalWeight)
 		fmt.Printf("\n%s/%s\n\n", weightageMarkStr, maxMarkSumStr)
 	}
 
-	doc.Find(MarksGPASpanSelector).Each(func(i int, s *goquery.Selection) {
-		gpa := s.Text()
+	for _, span := range report.NotIncludedInGPA {
 		fmt.Println("\x1b[32;1mCourse not included in GPA/CGPA\x1b[0m")
-		fmt.Println(gpa)
+		fmt.Println(span)
+	}
+
+	return report, nil
+}
+
+// GetMarksQuiet resolves the semester via semChoice exactly like GetMarks,
+// but performs no printing, for headless callers like the daemon package
+// that poll on a schedule and handle the result (or diff it) themselves.
+func GetMarksQuiet(regNo string, cookies types.Cookies, semChoice int) (types.MarksReport, error) {
+	if !helpers.ValidateLogin(cookies) {
+		return types.MarksReport{}, fmt.Errorf("invalid login")
+	}
+
+	semester, err := helpers.SelectSemester(regNo, cookies, semChoice)
+	if err != nil {
+		return types.MarksReport{}, err
+	}
+
+	return fetchMarksReportForSemester(regNo, cookies, semester.SemID)
+}
+
+// BuildMarksReport is the pure core of GetMarks: given an already-fetched
+// document it parses courses, components, and the "not included in GPA"
+// spans into a types.MarksReport with no printing or I/O, so formatters
+// (table/json/csv) and tests can all consume the same scrape.
+func BuildMarksReport(doc *goquery.Document, regNo, semID string) types.MarksReport {
+	return buildMarksReport(doc, regNo, semID, DefaultMarksSchema)
+}
+
+func buildMarksReport(doc *goquery.Document, regNo, semID string, schema MarksSchema) types.MarksReport {
+	report := types.MarksReport{RegNo: regNo, SemID: semID}
+
+	courseDetails := subjectDetails(doc, schema)
+	elements := findElementsByClass(doc, schema.CustomTableSelectors)
+
+	for idx, course := range courseDetails {
+		if idx >= len(elements) {
+			if debug.Debug {
+				fmt.Printf("No corresponding table found for course: %s\n", course.CourseTitle)
+			}
+			continue
+		}
+
+		rows, weightageMark, maxMarkSum := ExtractMarks(elements[idx], schema)
+
+		var components []types.CourseMarksComponent
+		for _, row := range rows {
+			if len(row) < 6 {
+				continue
+			}
+			component := types.CourseMarksComponent{Title: row[0], Status: row[3]}
+			fmt.Sscanf(row[1], "%f", &component.MaxMarks)
+			fmt.Sscanf(row[2], "%f", &component.Weightage)
+			fmt.Sscanf(row[4], "%f", &component.ScoredMarks)
+			fmt.Sscanf(row[5], "%f", &component.WeightageMark)
+			components = append(components, component)
+		}
+
+		report.Courses = append(report.Courses, types.CourseMarksSummary{
+			CourseCode:  course.CourseCode,
+			CourseTitle: course.CourseTitle,
+			CourseType:  course.CourseType,
+			Faculty:     course.Faculty,
+			Slot:        course.Slot,
+			Components:  components,
+			TotalScored: weightageMark,
+			TotalWeight: float64(maxMarkSum),
+		})
+	}
+
+	firstMatch(doc, schema.GPASpanSelectors).Each(func(i int, s *goquery.Selection) {
+		report.NotIncludedInGPA = append(report.NotIncludedInGPA, s.Text())
 	})
+
+	return report
 }
 
-func subjectDetails(doc *goquery.Document) []types.CourseDetail {
+// subjectDetails reads one types.CourseDetail per course row. Rows are
+// identified by having a non-empty, non-header course-code cell rather
+// than by a fixed odd/even row parity, so an extra separator or banner row
+// VTOP inserts doesn't throw off every course after it.
+func subjectDetails(doc *goquery.Document, schema MarksSchema) []types.CourseDetail {
 	var details []types.CourseDetail
 
-	doc.Find(MarksTableContentSelector).Each(func(i int, s *goquery.Selection) {
-		if i%2 != 0 {
+	firstMatch(doc, schema.TableContentSelectors).Each(func(i int, s *goquery.Selection) {
+		td := s.Find(schema.CellSelector)
+		courseCode := strings.TrimSpace(td.Eq(schema.CourseCodeCellIndex).Text())
+		if courseCode == "" || isHeaderCell(courseCode, schema.HeaderCellText) {
 			return
 		}
 
-		td := s.Find(MarksCellSelector)
-		courseCode := strings.TrimSpace(td.Eq(CourseCodeCellIndex).Text())
-		courseTitle := strings.TrimSpace(td.Eq(CourseTitleCellIndex).Text())
-		courseType := strings.TrimSpace(td.Eq(CourseTypeCellIndex).Text())
-		faculty := strings.TrimSpace(td.Eq(CourseFacultyCellIndex).Text())
-		slot := strings.TrimSpace(td.Eq(CourseSlotCellIndex).Text())
-
-		course := types.CourseDetail{
+		details = append(details, types.CourseDetail{
 			CourseCode:  courseCode,
-			CourseTitle: courseTitle,
-			CourseType:  courseType,
-			Faculty:     faculty,
-			Slot:        slot,
-		}
-
-		details = append(details, course)
+			CourseTitle: strings.TrimSpace(td.Eq(schema.CourseTitleCellIndex).Text()),
+			CourseType:  strings.TrimSpace(td.Eq(schema.CourseTypeCellIndex).Text()),
+			Faculty:     strings.TrimSpace(td.Eq(schema.CourseFacultyCellIndex).Text()),
+			Slot:        strings.TrimSpace(td.Eq(schema.CourseSlotCellIndex).Text()),
+		})
 	})
 	return details
 }
 
-func findElementsByClass(doc *goquery.Document, class string) []*goquery.Selection {
+func findElementsByClass(doc *goquery.Document, classes []string) []*goquery.Selection {
 	var result []*goquery.Selection
 
-	doc.Find("." + class).Each(func(_ int, selection *goquery.Selection) {
+	firstMatch(doc, classSelectors(classes)).Each(func(_ int, selection *goquery.Selection) {
 		result = append(result, selection)
 	})
 
 	return result
 }
 
-func ExtractMarks(element *goquery.Selection) ([][]string, float64, int) {
+func classSelectors(classes []string) []string {
+	selectors := make([]string, len(classes))
+	for i, class := range classes {
+		selectors[i] = "." + class
+	}
+	return selectors
+}
+
+// firstMatch tries each selector against doc in order, returning the first
+// one that matches at least one element. If none match, it returns the
+// (empty) result of the last selector, so callers can still range/Each
+// over it without a nil check.
+func firstMatch(doc *goquery.Document, selectors []string) *goquery.Selection {
+	var last *goquery.Selection
+	for _, sel := range selectors {
+		last = doc.Find(sel)
+		if last.Length() > 0 {
+			return last
+		}
+	}
+	return last
+}
+
+func isHeaderCell(text string, headerTexts []string) bool {
+	for _, h := range headerTexts {
+		if text == h {
+			return true
+		}
+	}
+	return false
+}
+
+func ExtractMarks(element *goquery.Selection, schema MarksSchema) ([][]string, float64, int) {
 	var SingleSubTable [][]string
 	var weightageMarkSum float64
 	var maxSubjectMarksSum int
 
-	element.Find(MarksRowsSelector).Each(func(_ int, rowSelection *goquery.Selection) {
-		firstCell := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(0).Text())
-		if firstCell == "Sl.No." || firstCell == "Index" || firstCell == "" {
+	element.Find(schema.RowsSelector).Each(func(_ int, rowSelection *goquery.Selection) {
+		firstCell := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(0).Text())
+		if firstCell == "" || isHeaderCell(firstCell, schema.HeaderCellText) {
 			return
 		}
 
-		title := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksTitleCellIndex).Text())
-		maxMark := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksMaxMarkCellIndex).Text())
-		weightage := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksWeightageCellIndex).Text())
-		status := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksStatusCellIndex).Text())
-		scoredMark := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksScoredMarkCellIndex).Text())
-		weightageMark := strings.TrimSpace(rowSelection.Find(MarksCellSelector).Eq(MarksWeightageMarkCellIndex).Text())
+		title := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.TitleCellIndex).Text())
+		maxMark := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.MaxMarkCellIndex).Text())
+		weightage := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.WeightageCellIndex).Text())
+		status := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.StatusCellIndex).Text())
+		scoredMark := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.ScoredMarkCellIndex).Text())
+		weightageMark := strings.TrimSpace(rowSelection.Find(schema.CellSelector).Eq(schema.WeightageMarkCellIndex).Text())
 
 		SingleSubTable = append(SingleSubTable, []string{title, maxMark, weightage, status, scoredMark, weightageMark})
 
@@ -179,7 +331,7 @@ func ExtractMarks(element *goquery.Selection) ([][]string, float64, int) {
 			weightageMarkSum += weightageFloat
 		}
 
-		maxMarkInt, err := strconv.Atoi(weightage)
+		maxMarkInt, err := strconv.Atoi(maxMark)
 		if err == nil {
 			maxSubjectMarksSum += maxMarkInt
 		}
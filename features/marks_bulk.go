@@ -0,0 +1,132 @@
+// features/marks_bulk.go
+package features
+
+import (
+	"bytes"
+	"cli-top/helpers"
+	"cli-top/types"
+	"cli-top/vtop"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultMarksWorkers is how many semesters GetAllMarks fetches concurrently
+// when the caller doesn't override it.
+const DefaultMarksWorkers = 4
+
+const (
+	marksRetryAttempts = 3
+	marksRetryBaseWait = 250 * time.Millisecond
+)
+
+// GetAllMarks fans out across semesterIDs with a bounded worker pool,
+// retrying transient HTTP errors with backoff, and returns one MarksReport
+// per semester in the same order as semesterIDs (a zero-value MarksReport,
+// with an empty SemID, where a semester failed after all retries). The whole pipeline is ctx-cancellable so a
+// caller can abort in-flight requests (e.g. on Ctrl-C).
+func GetAllMarks(ctx context.Context, regNo string, cookies types.Cookies, semesterIDs []string, workers int) ([]types.MarksReport, error) {
+	if !helpers.ValidateLogin(cookies) {
+		return nil, fmt.Errorf("invalid login")
+	}
+
+	if workers <= 0 {
+		workers = DefaultMarksWorkers
+	}
+	if workers > len(semesterIDs) {
+		workers = len(semesterIDs)
+	}
+
+	reports := make([]types.MarksReport, len(semesterIDs))
+	errs := make([]error, len(semesterIDs))
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				report, err := fetchMarksReportWithRetry(ctx, regNo, cookies, semesterIDs[idx])
+				reports[idx], errs[idx] = report, err
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for idx := range semesterIDs {
+			select {
+			case jobs <- idx:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	var failures int
+	for _, err := range errs {
+		if err != nil {
+			failures++
+		}
+	}
+	if failures == len(semesterIDs) && len(semesterIDs) > 0 {
+		return reports, fmt.Errorf("all %d semester fetches failed", failures)
+	}
+	if ctx.Err() != nil {
+		return reports, ctx.Err()
+	}
+
+	return reports, nil
+}
+
+// fetchMarksReportWithRetry wraps fetchMarksReportForSemester with bounded
+// exponential backoff on transient errors.
+func fetchMarksReportWithRetry(ctx context.Context, regNo string, cookies types.Cookies, semID string) (types.MarksReport, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < marksRetryAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return types.MarksReport{}, ctx.Err()
+		}
+
+		report, err := fetchMarksReportForSemester(regNo, cookies, semID)
+		if err == nil {
+			return report, nil
+		}
+		lastErr = err
+
+		wait := marksRetryBaseWait * time.Duration(1<<attempt)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return types.MarksReport{}, ctx.Err()
+		}
+	}
+
+	return types.MarksReport{}, fmt.Errorf("semester %s: %w", semID, lastErr)
+}
+
+// fetchMarksReportForSemester is GetMarks' fetch-and-parse core for an
+// explicit semester ID, without the interactive semester selection or
+// terminal printing.
+func fetchMarksReportForSemester(regNo string, cookies types.Cookies, semID string) (types.MarksReport, error) {
+	bodyText, err := vtop.New(regNo, cookies).FetchMarks(semID)
+	if err != nil {
+		return types.MarksReport{}, err
+	}
+	dumpHTML(fmt.Sprintf("marks-%s-%s.html", regNo, semID), bodyText)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyText))
+	if err != nil {
+		return types.MarksReport{}, err
+	}
+
+	return BuildMarksReport(doc, regNo, semID), nil
+}
@@ -0,0 +1,95 @@
+package features
+
+import (
+	"os"
+	"testing"
+
+	"cli-top/types"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func loadTestdataDoc(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("opening %s: %v", name, err)
+	}
+	defer f.Close()
+
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", name, err)
+	}
+	return doc
+}
+
+func TestSubjectDetails_MarksSample(t *testing.T) {
+	doc := loadTestdataDoc(t, "marks_sample.html")
+
+	details := subjectDetails(doc, DefaultMarksSchema)
+	if len(details) != 1 {
+		t.Fatalf("expected 1 course, got %d: %+v", len(details), details)
+	}
+
+	want := types.CourseDetail{
+		CourseCode:  "CSE1001",
+		CourseTitle: "Problem Solving",
+		CourseType:  "Theory",
+		Faculty:     "Dr. Faculty",
+		Slot:        "A1+TA1",
+	}
+	if details[0] != want {
+		t.Fatalf("got %+v, want %+v", details[0], want)
+	}
+}
+
+func TestExtractMarks_MarksSample(t *testing.T) {
+	doc := loadTestdataDoc(t, "marks_sample.html")
+
+	elements := findElementsByClass(doc, DefaultMarksSchema.CustomTableSelectors)
+	if len(elements) != 1 {
+		t.Fatalf("expected 1 marks table, got %d", len(elements))
+	}
+
+	rows, weightageMark, maxMarkSum := ExtractMarks(elements[0], DefaultMarksSchema)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 component row, got %d: %+v", len(rows), rows)
+	}
+
+	wantRow := []string{"CAT1", "50", "15", "Published", "40", "12"}
+	for i, want := range wantRow {
+		if rows[0][i] != want {
+			t.Fatalf("row[%d] = %q, want %q (row: %+v)", i, rows[0][i], want, rows[0])
+		}
+	}
+
+	if weightageMark != 12 {
+		t.Fatalf("weightageMark = %v, want 12", weightageMark)
+	}
+	if maxMarkSum != 50 {
+		t.Fatalf("maxMarkSum = %v, want 50", maxMarkSum)
+	}
+}
+
+func TestBuildMarksReport_MarksSample(t *testing.T) {
+	doc := loadTestdataDoc(t, "marks_sample.html")
+
+	report := BuildMarksReport(doc, "21BCE0001", "SEM1")
+
+	if len(report.Courses) != 1 {
+		t.Fatalf("expected 1 course, got %d", len(report.Courses))
+	}
+	course := report.Courses[0]
+	if course.CourseCode != "CSE1001" || len(course.Components) != 1 {
+		t.Fatalf("unexpected course: %+v", course)
+	}
+	if course.Components[0].ScoredMarks != 40 {
+		t.Fatalf("ScoredMarks = %v, want 40", course.Components[0].ScoredMarks)
+	}
+
+	if len(report.NotIncludedInGPA) != 1 || report.NotIncludedInGPA[0] != "MAT1001 - Calculus" {
+		t.Fatalf("expected the fallback GPASpanSelectors entry to match, got %+v", report.NotIncludedInGPA)
+	}
+}
@@ -0,0 +1,95 @@
+// Package metrics exposes the data features.BuildAIData collects as
+// Prometheus gauges, turning the CLI into a scrape target for a personal
+// dashboard.
+package metrics
+
+import (
+	"time"
+
+	"cli-top/features"
+	"cli-top/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	AttendancePercent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vtop_attendance_percent",
+		Help: "Current attendance percentage per course.",
+	}, []string{"course", "type", "faculty"})
+
+	AttendanceCanMiss = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vtop_attendance_can_miss",
+		Help: "Classes that can still be missed before dropping below the attendance threshold (negative means classes owed).",
+	}, []string{"course"})
+
+	CGPA = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vtop_cgpa",
+		Help: "Current CGPA.",
+	})
+
+	CreditsEarned = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vtop_credits_earned",
+		Help: "Total credits earned so far.",
+	})
+
+	MarksScored = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vtop_marks_scored",
+		Help: "Scored marks per course component.",
+	}, []string{"course", "component"})
+
+	ExamSecondsUntil = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vtop_exam_seconds_until",
+		Help: "Seconds until each upcoming exam starts (negative if already past).",
+	}, []string{"course"})
+
+	LastRefreshTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vtop_last_refresh_timestamp",
+		Help: "Unix timestamp of the last successful data refresh, so scrapers can alarm on staleness.",
+	})
+)
+
+// Registry is a dedicated registry (rather than the global default) so
+// `cli-top serve` only exposes the gauges above, not Go runtime metrics.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		AttendancePercent,
+		AttendanceCanMiss,
+		CGPA,
+		CreditsEarned,
+		MarksScored,
+		ExamSecondsUntil,
+		LastRefreshTimestamp,
+	)
+}
+
+// Update refreshes every gauge from a freshly (or stale-but-cached) collected
+// VTOPAIData snapshot plus the CGPA/credits pair PrintCgpa already parses.
+func Update(data types.VTOPAIData, cgpaSnapshot types.CGPASnapshot, refreshedAt time.Time) {
+	AttendancePercent.Reset()
+	for _, a := range data.Attendance {
+		AttendancePercent.WithLabelValues(a.CourseCode, a.CourseType, a.Faculty).Set(a.Percentage)
+		gap := features.AttendanceGap(a.Attended, a.Total, features.DefaultAttendanceThreshold)
+		AttendanceCanMiss.WithLabelValues(a.CourseCode).Set(float64(gap))
+	}
+
+	CGPA.Set(cgpaSnapshot.CGPA)
+	CreditsEarned.Set(float64(cgpaSnapshot.CreditsEarned))
+
+	MarksScored.Reset()
+	for _, course := range data.Marks {
+		for _, component := range course.Components {
+			MarksScored.WithLabelValues(course.CourseCode, component.Title).Set(component.ScoredMarks)
+		}
+	}
+
+	ExamSecondsUntil.Reset()
+	now := time.Now()
+	for _, e := range data.Exams {
+		ExamSecondsUntil.WithLabelValues(e.CourseCode).Set(e.StartsAt.Sub(now).Seconds())
+	}
+
+	LastRefreshTimestamp.Set(float64(refreshedAt.Unix()))
+}
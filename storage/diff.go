@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"strconv"
+
+	"cli-top/types"
+)
+
+// ChangeKind categorizes one difference between two MarksReport snapshots.
+type ChangeKind string
+
+const (
+	ChangeNewComponent ChangeKind = "new_component"
+	ChangeScoredMarks  ChangeKind = "scored_marks_changed"
+	ChangeStatus       ChangeKind = "status_changed"
+)
+
+// Change describes one difference found by Diff.
+type Change struct {
+	CourseCode string
+	Component  string
+	Kind       ChangeKind
+	Old        string
+	New        string
+}
+
+// Diff compares previous against current and reports new components,
+// changed scored marks, and changed statuses. Courses or components that
+// disappeared between scrapes aren't reported: VTOP doesn't retract
+// published marks, so a missing row is treated as not-yet-refreshed rather
+// than as a change.
+func Diff(previous, current types.MarksReport) []Change {
+	type key struct{ course, component string }
+	seen := make(map[key]types.CourseMarksComponent)
+
+	for _, course := range previous.Courses {
+		for _, c := range course.Components {
+			seen[key{course.CourseCode, c.Title}] = c
+		}
+	}
+
+	var changes []Change
+	for _, course := range current.Courses {
+		for _, c := range course.Components {
+			k := key{course.CourseCode, c.Title}
+			old, existed := seen[k]
+
+			if !existed {
+				changes = append(changes, Change{
+					CourseCode: course.CourseCode,
+					Component:  c.Title,
+					Kind:       ChangeNewComponent,
+					New:        formatScored(c),
+				})
+				continue
+			}
+
+			if old.ScoredMarks != c.ScoredMarks {
+				changes = append(changes, Change{
+					CourseCode: course.CourseCode,
+					Component:  c.Title,
+					Kind:       ChangeScoredMarks,
+					Old:        formatScored(old),
+					New:        formatScored(c),
+				})
+			}
+
+			if old.Status != c.Status {
+				changes = append(changes, Change{
+					CourseCode: course.CourseCode,
+					Component:  c.Title,
+					Kind:       ChangeStatus,
+					Old:        old.Status,
+					New:        c.Status,
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+func formatScored(c types.CourseMarksComponent) string {
+	return formatFloat(c.ScoredMarks) + "/" + formatFloat(c.MaxMarks)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,243 @@
+// Package storage persists scraped MarksReports to SQLite (via
+// modernc.org/sqlite, so no CGo is needed) and diffs each new scrape against
+// the last one saved, enabling an offline history view and a
+// "what changed since last check" mode.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cli-top/types"
+
+	_ "modernc.org/sqlite"
+)
+
+// MarksRepository persists and retrieves scraped marks snapshots.
+type MarksRepository interface {
+	Save(report types.MarksReport) error
+	LatestFor(regNo, semID string) (types.MarksReport, bool, error)
+	History(regNo, semID string) ([]types.MarksReport, error)
+}
+
+// SQLiteMarksRepository is the default MarksRepository, storing one row per
+// (regNo, semID, courseCode, componentTitle, fetchedAt).
+type SQLiteMarksRepository struct {
+	db *sql.DB
+}
+
+// DefaultMarksPath returns the repository's database path under the user's
+// config dir.
+func DefaultMarksPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cli-top", "marks-history.db"), nil
+}
+
+// NewSQLiteMarksRepository opens (creating if necessary) the repository
+// database at path. Pass ":memory:" for an ephemeral, test-only instance.
+func NewSQLiteMarksRepository(path string) (*SQLiteMarksRepository, error) {
+	if path != ":memory:" {
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("creating marks history dir: %w", err)
+			}
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening marks history db: %w", err)
+	}
+
+	repo := &SQLiteMarksRepository{db: db}
+	if err := repo.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating marks history db: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *SQLiteMarksRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteMarksRepository) migrate() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS marks_history (
+			reg_no          TEXT    NOT NULL,
+			sem_id          TEXT    NOT NULL,
+			course_code     TEXT    NOT NULL,
+			course_title    TEXT    NOT NULL,
+			component_title TEXT    NOT NULL,
+			max_marks       REAL    NOT NULL,
+			weightage       REAL    NOT NULL,
+			status          TEXT    NOT NULL,
+			scored_marks    REAL    NOT NULL,
+			weightage_mark  REAL    NOT NULL,
+			fetched_at      INTEGER NOT NULL,
+			PRIMARY KEY (reg_no, sem_id, course_code, component_title, fetched_at)
+		)
+	`)
+	return err
+}
+
+// Save persists every component of report under a single fetched_at
+// timestamp, so later queries can group rows back into one snapshot.
+func (r *SQLiteMarksRepository) Save(report types.MarksReport) error {
+	fetchedAt := time.Now().UnixNano()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO marks_history (
+			reg_no, sem_id, course_code, course_title, component_title,
+			max_marks, weightage, status, scored_marks, weightage_mark, fetched_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, course := range report.Courses {
+		for _, c := range course.Components {
+			if _, err := stmt.Exec(
+				report.RegNo, report.SemID, course.CourseCode, course.CourseTitle, c.Title,
+				c.MaxMarks, c.Weightage, c.Status, c.ScoredMarks, c.WeightageMark, fetchedAt,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LatestFor reconstructs the most recently saved snapshot for (regNo, semID).
+func (r *SQLiteMarksRepository) LatestFor(regNo, semID string) (types.MarksReport, bool, error) {
+	var latest sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT MAX(fetched_at) FROM marks_history WHERE reg_no = ? AND sem_id = ?
+	`, regNo, semID).Scan(&latest)
+	if err != nil {
+		return types.MarksReport{}, false, err
+	}
+	if !latest.Valid {
+		return types.MarksReport{}, false, nil
+	}
+
+	report, err := r.snapshotAt(regNo, semID, latest.Int64)
+	return report, true, err
+}
+
+// LatestSemID returns the semester ID of the most recently saved snapshot
+// for regNo, for callers like `cli-top marks --history` that want the last
+// semester checked without asking the user to specify it again.
+func (r *SQLiteMarksRepository) LatestSemID(regNo string) (string, bool, error) {
+	var semID sql.NullString
+	err := r.db.QueryRow(`
+		SELECT sem_id FROM marks_history
+		WHERE reg_no = ?
+		ORDER BY fetched_at DESC
+		LIMIT 1
+	`, regNo).Scan(&semID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return semID.String, true, nil
+}
+
+// History returns every saved snapshot for (regNo, semID), oldest first.
+func (r *SQLiteMarksRepository) History(regNo, semID string) ([]types.MarksReport, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT fetched_at FROM marks_history
+		WHERE reg_no = ? AND sem_id = ?
+		ORDER BY fetched_at ASC
+	`, regNo, semID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var timestamps []int64
+	for rows.Next() {
+		var ts int64
+		if err := rows.Scan(&ts); err != nil {
+			return nil, err
+		}
+		timestamps = append(timestamps, ts)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	reports := make([]types.MarksReport, 0, len(timestamps))
+	for _, ts := range timestamps {
+		report, err := r.snapshotAt(regNo, semID, ts)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (r *SQLiteMarksRepository) snapshotAt(regNo, semID string, fetchedAt int64) (types.MarksReport, error) {
+	rows, err := r.db.Query(`
+		SELECT course_code, course_title, component_title, max_marks, weightage, status, scored_marks, weightage_mark
+		FROM marks_history
+		WHERE reg_no = ? AND sem_id = ? AND fetched_at = ?
+		ORDER BY course_code, component_title
+	`, regNo, semID, fetchedAt)
+	if err != nil {
+		return types.MarksReport{}, err
+	}
+	defer rows.Close()
+
+	report := types.MarksReport{RegNo: regNo, SemID: semID}
+	coursesByCode := make(map[string]*types.CourseMarksSummary)
+	var order []string
+
+	for rows.Next() {
+		var courseCode, courseTitle string
+		var component types.CourseMarksComponent
+
+		if err := rows.Scan(&courseCode, &courseTitle, &component.Title, &component.MaxMarks, &component.Weightage, &component.Status, &component.ScoredMarks, &component.WeightageMark); err != nil {
+			return types.MarksReport{}, err
+		}
+
+		course, ok := coursesByCode[courseCode]
+		if !ok {
+			course = &types.CourseMarksSummary{CourseCode: courseCode, CourseTitle: courseTitle}
+			coursesByCode[courseCode] = course
+			order = append(order, courseCode)
+		}
+		course.Components = append(course.Components, component)
+		course.TotalScored += component.WeightageMark
+		course.TotalWeight += component.MaxMarks
+	}
+	if err := rows.Err(); err != nil {
+		return types.MarksReport{}, err
+	}
+
+	for _, code := range order {
+		report.Courses = append(report.Courses, *coursesByCode[code])
+	}
+
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"testing"
+
+	"cli-top/types"
+)
+
+func sampleReport(scored float64, status string) types.MarksReport {
+	return types.MarksReport{
+		RegNo: "21BCE0001",
+		SemID: "SEM1",
+		Courses: []types.CourseMarksSummary{
+			{
+				CourseCode:  "CSE1001",
+				CourseTitle: "Problem Solving",
+				Components: []types.CourseMarksComponent{
+					{Title: "CAT1", MaxMarks: 50, Weightage: 15, Status: status, ScoredMarks: scored, WeightageMark: scored * 0.3},
+				},
+			},
+		},
+	}
+}
+
+func TestSQLiteMarksRepository_SaveAndLatest(t *testing.T) {
+	repo, err := NewSQLiteMarksRepository(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteMarksRepository: %v", err)
+	}
+	defer repo.Close()
+
+	if _, ok, err := repo.LatestFor("21BCE0001", "SEM1"); err != nil || ok {
+		t.Fatalf("expected no snapshot yet, got ok=%v err=%v", ok, err)
+	}
+
+	if err := repo.Save(sampleReport(40, "Published")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	latest, ok, err := repo.LatestFor("21BCE0001", "SEM1")
+	if err != nil || !ok {
+		t.Fatalf("LatestFor: ok=%v err=%v", ok, err)
+	}
+	if len(latest.Courses) != 1 || latest.Courses[0].Components[0].ScoredMarks != 40 {
+		t.Fatalf("unexpected latest snapshot: %+v", latest)
+	}
+}
+
+func TestSQLiteMarksRepository_History(t *testing.T) {
+	repo, err := NewSQLiteMarksRepository(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteMarksRepository: %v", err)
+	}
+	defer repo.Close()
+
+	if err := repo.Save(sampleReport(30, "Published")); err != nil {
+		t.Fatalf("Save #1: %v", err)
+	}
+	if err := repo.Save(sampleReport(40, "Published")); err != nil {
+		t.Fatalf("Save #2: %v", err)
+	}
+
+	history, err := repo.History("21BCE0001", "SEM1")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(history))
+	}
+	if history[0].Courses[0].Components[0].ScoredMarks != 30 {
+		t.Fatalf("expected snapshots in chronological order, got %+v", history[0])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := sampleReport(30, "Published")
+	current := sampleReport(40, "Published")
+
+	changes := Diff(previous, current)
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Kind != ChangeScoredMarks {
+		t.Fatalf("expected a scored-marks change, got %v", changes[0].Kind)
+	}
+}
+
+func TestDiff_NewComponent(t *testing.T) {
+	previous := types.MarksReport{RegNo: "21BCE0001", SemID: "SEM1"}
+	current := sampleReport(40, "Published")
+
+	changes := Diff(previous, current)
+	if len(changes) != 1 || changes[0].Kind != ChangeNewComponent {
+		t.Fatalf("expected 1 new-component change, got %+v", changes)
+	}
+}
@@ -0,0 +1,165 @@
+// Package vtop provides a typed client for the handful of VTOP endpoints
+// cli-top scrapes. Before this package existed, every fetcher in
+// features/*.go hand-formatted its own multipart/form-data payload string
+// with a hardcoded boundary and manual CRLFs; Client centralizes that
+// construction behind mime/multipart.Writer, plus the debug logging around
+// helpers.FetchReq, so a new endpoint is a one-method addition instead of a
+// copy-pasted string template.
+package vtop
+
+import (
+	"bytes"
+	"cli-top/cache"
+	"cli-top/debug"
+	"cli-top/helpers"
+	"cli-top/types"
+	"fmt"
+	"mime/multipart"
+)
+
+// Store and Mode configure the cache every Client.postForm call goes
+// through. A cmd package sets these once (via cache.Open and the
+// --refresh/--offline flags) before the first fetch; leaving Store nil
+// falls back to an uncached fetch, which is what tests and any caller that
+// never configures the cache get.
+var (
+	Store *cache.Store
+	Mode  cache.Mode
+)
+
+// boundary matches the multipart boundary cli-top's HTTP layer has always
+// sent; keeping it fixed (instead of multipart.Writer's random default)
+// means Client is a drop-in replacement for the old hand-formatted bodies.
+const boundary = "----WebKitFormBoundary9yjNZXu7BBjgQK7J"
+
+// Client wraps a student's VTOP session (registration number plus
+// cookies/CSRF) and exposes one typed method per scraped endpoint.
+type Client struct {
+	RegNo   string
+	Cookies types.Cookies
+}
+
+// New builds a Client for regNo/cookies.
+func New(regNo string, cookies types.Cookies) *Client {
+	return &Client{RegNo: regNo, Cookies: cookies}
+}
+
+// FetchMarks fetches the raw marks page for semID.
+func (c *Client) FetchMarks(semID string) ([]byte, error) {
+	return c.postForm(
+		"https://vtop.vit.ac.in/vtop/examinations/doStudentMarkView",
+		semID, "marks",
+		fields{"semesterSubId": semID},
+	)
+}
+
+// FetchAttendanceReport fetches the raw attendance report page for semID.
+func (c *Client) FetchAttendanceReport(semID string) ([]byte, error) {
+	return c.postForm(
+		"https://vtop.vit.ac.in/vtop/student/attn_report",
+		semID, "attendance",
+		fields{"semesterSubId": semID},
+	)
+}
+
+// FetchAttendanceDetail fetches the raw per-subject attendance detail page
+// for semID — the older processViewStudentAttendance endpoint `cli-top
+// attendance`/`cli-top export attendance` scrape, distinct from
+// FetchAttendanceReport's attn_report page used by the AI data builder.
+func (c *Client) FetchAttendanceDetail(semID string) ([]byte, error) {
+	return c.postForm(
+		"https://vtop.vit.ac.in/vtop/processViewStudentAttendance",
+		semID, "attendance-detail",
+		fields{"semesterSubId": semID},
+	)
+}
+
+// FetchTimetable fetches the raw weekly timetable page for semID.
+func (c *Client) FetchTimetable(semID string) ([]byte, error) {
+	return c.postForm(
+		"https://vtop.vit.ac.in/vtop/examinations/doSearchCandidateTimetable",
+		semID, "timetable",
+		fields{"semesterSubId": semID},
+	)
+}
+
+// FetchGradeDistribution fetches the raw per-course grade-distribution
+// report used by the GPA projection feature.
+func (c *Client) FetchGradeDistribution(courseCode string) ([]byte, error) {
+	return c.postForm(
+		"https://vtop.vit.ac.in/vtop/examinations/examGradeView/StudentGradeHistory",
+		courseCode, "grade-distribution",
+		fields{"courseCode": courseCode},
+	)
+}
+
+// FetchCGPA fetches a CGPA-family report at url with no extra form fields
+// beyond authorizedID/_csrf — PrintCgpa's whole-semester summary and
+// collectAICGPA's grade-history trend both fetch this way, just from
+// different URLs, so cacheKey tells their cache entries apart.
+func (c *Client) FetchCGPA(url, cacheKey string) ([]byte, error) {
+	return c.postForm(url, cacheKey, "cgpa", fields{})
+}
+
+// fields are the form-data parts beyond authorizedID and _csrf, which
+// every endpoint below sends the same way.
+type fields map[string]string
+
+// postForm builds a multipart/form-data body (authorizedID and _csrf plus
+// extra) and posts it through the cache (if Store is configured) or
+// directly through helpers.FetchReq, logging the error under debug.Debug
+// like every fetcher used to do inline.
+func (c *Client) postForm(url, cacheKey, kind string, extra fields) ([]byte, error) {
+	if !helpers.ValidateLogin(c.Cookies) {
+		return nil, fmt.Errorf("invalid login")
+	}
+
+	body, err := c.buildMultipart(extra)
+	if err != nil {
+		return nil, fmt.Errorf("building %s request: %w", kind, err)
+	}
+
+	var bodyText []byte
+	if Store != nil {
+		bodyText, _, err = cache.FetchMode(Store, Mode, helpers.FetchReq, c.RegNo, c.Cookies, url, cacheKey, body, "POST", kind)
+	} else {
+		bodyText, err = helpers.FetchReq(c.RegNo, c.Cookies, url, cacheKey, body, "POST", kind)
+	}
+	if err != nil {
+		if debug.Debug {
+			fmt.Println(err)
+		}
+		return nil, err
+	}
+
+	return bodyText, nil
+}
+
+// buildMultipart writes authorizedID, every key in extra, and _csrf (in
+// that order, matching the field order the old hand-formatted payloads
+// used) into a multipart/form-data body with the fixed boundary.
+func (c *Client) buildMultipart(extra fields) (string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(boundary); err != nil {
+		return "", err
+	}
+
+	if err := w.WriteField("authorizedID", c.RegNo); err != nil {
+		return "", err
+	}
+	for key, value := range extra {
+		if err := w.WriteField(key, value); err != nil {
+			return "", err
+		}
+	}
+	if err := w.WriteField("_csrf", c.Cookies.CSRF); err != nil {
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
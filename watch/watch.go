@@ -0,0 +1,168 @@
+// Package watch runs BuildAIData on a timer and fires desktop notifications
+// for the things a student would otherwise only see by manually running
+// `cli-top attendance` or `cli-top exams`: attendance breaches and upcoming
+// exams. There is no "class starting soon" notice: types.TimetableEntry only
+// carries a day of week, never a real start time.
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	"cli-top/features"
+	"cli-top/types"
+
+	"github.com/gen2brain/beeep"
+	"github.com/kardianos/service"
+)
+
+// Config controls the poll cadence and alert thresholds. PollInterval and
+// the quiet hours are global; Thresholds overrides the default 74.01%
+// attendance requirement per course code.
+type Config struct {
+	PollInterval   time.Duration
+	QuietHourStart int // 0-23, inclusive
+	QuietHourEnd   int // 0-23, exclusive
+	Thresholds     map[string]float64
+	ExamLeadTime   time.Duration
+}
+
+// DefaultConfig matches what the request calls for: a 75%-ish attendance
+// floor and a 48h exam heads-up.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:   10 * time.Minute,
+		QuietHourStart: 22,
+		QuietHourEnd:   7,
+		Thresholds:     map[string]float64{},
+		ExamLeadTime:   48 * time.Hour,
+	}
+}
+
+// Program implements github.com/kardianos/service's Interface so `cli-top
+// watch` can install and run as a real background service as well as in the
+// foreground.
+type Program struct {
+	RegNo   string
+	Cookies types.Cookies
+	Config  Config
+
+	notifiedExams map[string]bool
+	stop          chan struct{}
+}
+
+func NewProgram(regNo string, cookies types.Cookies, cfg Config) *Program {
+	return &Program{
+		RegNo:         regNo,
+		Cookies:       cookies,
+		Config:        cfg,
+		notifiedExams: make(map[string]bool),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start satisfies service.Interface; the actual polling runs in a goroutine
+// so the service manager's Start call returns promptly.
+func (p *Program) Start(s service.Service) error {
+	go p.run()
+	return nil
+}
+
+// Stop satisfies service.Interface.
+func (p *Program) Stop(s service.Service) error {
+	close(p.stop)
+	return nil
+}
+
+func (p *Program) run() {
+	p.poll()
+
+	ticker := time.NewTicker(p.Config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+func (p *Program) poll() {
+	if p.inQuietHours(time.Now()) {
+		return
+	}
+
+	// The marks, attendance, timetable and CGPA fetches go through
+	// vtop.Client, so once loadSession has called configureCache
+	// (cmd/cache.go) they are served from the on-disk cache while fresh; only
+	// a cold/expired entry or --refresh reaches VTOP for those. The exam
+	// schedule fetch (collectAIExams) calls helpers.FetchReq directly and is
+	// not cached.
+	data, err := features.BuildAIData(p.RegNo, p.Cookies)
+	if err != nil {
+		// BuildAIData returns partial data alongside the error, so still
+		// check whatever came back instead of bailing out entirely.
+		fmt.Println("watch: partial data collection error:", err)
+	}
+
+	p.checkAttendance(data.Attendance)
+	p.checkExams(data.Exams)
+}
+
+func (p *Program) inQuietHours(now time.Time) bool {
+	hour := now.Hour()
+	if p.Config.QuietHourStart == p.Config.QuietHourEnd {
+		return false
+	}
+	if p.Config.QuietHourStart < p.Config.QuietHourEnd {
+		return hour >= p.Config.QuietHourStart && hour < p.Config.QuietHourEnd
+	}
+	// Wraps midnight, e.g. 22 -> 7.
+	return hour >= p.Config.QuietHourStart || hour < p.Config.QuietHourEnd
+}
+
+func (p *Program) thresholdFor(courseCode string) float64 {
+	if t, ok := p.Config.Thresholds[courseCode]; ok {
+		return t
+	}
+	return features.DefaultAttendanceThreshold
+}
+
+func (p *Program) checkAttendance(records []types.AttendanceRecord) {
+	for _, r := range records {
+		gap := features.AttendanceGap(r.Attended, r.Total, p.thresholdFor(r.CourseCode))
+		if gap < 0 {
+			notify(
+				fmt.Sprintf("Attendance alert: %s", r.CourseCode),
+				fmt.Sprintf("Attend %d more class(es) to stay above threshold (currently %.1f%%)", -gap, r.Percentage),
+			)
+		}
+	}
+}
+
+func (p *Program) checkExams(exams []types.ExamEvent) {
+	now := time.Now()
+	for _, e := range exams {
+		until := e.StartsAt.Sub(now)
+		if until <= 0 || until > p.Config.ExamLeadTime {
+			continue
+		}
+		if p.notifiedExams[e.CourseCode] {
+			continue
+		}
+		p.notifiedExams[e.CourseCode] = true
+		notify(
+			fmt.Sprintf("Upcoming exam: %s", e.CourseCode),
+			fmt.Sprintf("Starts %s (in %s)", e.StartsAt.Format("Mon Jan 2 15:04"), until.Round(time.Minute)),
+		)
+	}
+}
+
+func notify(title, message string) {
+	if err := beeep.Notify(title, message, ""); err != nil {
+		fmt.Println("watch: notification failed:", err)
+	}
+}